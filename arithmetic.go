@@ -6,6 +6,8 @@ package period
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/govalues/decimal"
@@ -52,21 +54,76 @@ func (period Period) AddTo(t time.Time) (time.Time, bool) {
 	return t.Add(d), precise
 }
 
+// DurationBetween measures how far t2 is beyond the end of the period when the period starts at t1.
+// The fields are added to t1 one by one, from years down to seconds, using time.AddDate for the
+// years, months, weeks and days and time.Add for the hours, minutes and seconds. The result is the
+// remaining difference between t2 and the accumulated time, so it is zero when the period exactly
+// spans from t1 to t2, and negative when t2 is before the end of the period.
+//
+// A flag is also returned that is true when no approximation was needed. If any of the years,
+// months, weeks or days contains a fraction, the end of the period is estimated as for AddTo.
+func (period Period) DurationBetween(t1, t2 time.Time) (time.Duration, bool) {
+	if !zeroCalendarValues(period) && !wholeCalendarValues(period) {
+		end, precise := period.AddTo(t1)
+		return t2.Sub(end), precise
+	}
+
+	years, _, ok1 := period.GetField(Year).Int64(0)
+	months, _, ok2 := period.GetField(Month).Int64(0)
+	weeks, _, ok3 := period.GetField(Week).Int64(0)
+	days, _, ok4 := period.GetField(Day).Int64(0)
+
+	t := t1.AddDate(int(years), 0, 0)
+	t = t.AddDate(0, int(months), 0)
+	t = t.AddDate(0, 0, int(7*weeks))
+	t = t.AddDate(0, 0, int(days))
+
+	hh, ok5 := fieldDuration(period.GetField(Hour), int64(time.Hour))
+	mm, ok6 := fieldDuration(period.GetField(Minute), int64(time.Minute))
+	ss, ok7 := fieldDuration(period.GetField(Second), int64(time.Second))
+
+	t = t.Add(time.Duration(hh)).Add(time.Duration(mm)).Add(time.Duration(ss))
+
+	return t2.Sub(t), ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // Add adds two periods together. Use this method along with Negate in order to subtract periods.
+// The result is normalised using Normalise(true); see AddRaw for an alternative.
 // Arithmetic overflow will result in an error.
 func (period Period) Add(other Period) (Period, error) {
+	result, err := period.addFields(other)
+	return result.Normalise(true).NormaliseSign(), err
+}
+
+// AddRaw adds two periods together field by field, without normalising the result, so "P1Y"
+// plus "P12M" gives "P1Y12M" rather than "P2Y". Callers can apply Normalise or Simplify afterwards
+// as they choose. Only the overall sign is normalised (see NormaliseSign).
+//
+// As with NewDecimal, only the least significant non-zero field may contain a fraction, so for
+// example "P1.5Y" plus "P1M" gives an error; the sum "P1.5Y1M" is still returned but it will not
+// round-trip via String and Parse. Arithmetic overflow will also result in an error.
+func (period Period) AddRaw(other Period) (Period, error) {
+	result, err := period.addFields(other)
+	if err != nil {
+		return result, err
+	}
+	return NewDecimal(result.GetField(Year), result.GetField(Month), result.GetField(Week), result.GetField(Day),
+		result.GetField(Hour), result.GetField(Minute), result.GetField(Second))
+}
+
+func (period Period) addFields(other Period) (Period, error) {
 	var left, right Period
 
 	if period.neg {
-		left = period.flipSign()
+		left = period.FlipSign()
 	} else {
 		left = period
 	}
 
 	if other.neg {
-		right = other.flipSign()
+		right = other.FlipSign()
 	} else {
 		right = other
 	}
@@ -79,16 +136,44 @@ func (period Period) Add(other Period) (Period, error) {
 	minutes, e6 := left.minutes.Add(right.minutes)
 	seconds, e7 := left.seconds.Add(right.seconds)
 
-	result := Period{years: years, months: months, weeks: weeks, days: days, hours: hours, minutes: minutes, seconds: seconds}.Normalise(true).normaliseSign()
+	result := Period{years: years, months: months, weeks: weeks, days: days, hours: hours, minutes: minutes, seconds: seconds}.NormaliseSign()
 	return result, errors.Join(e1, e2, e3, e4, e5, e6, e7)
 }
 
+// MustAdd is as per Add except that it panics on arithmetic overflow.
+// This is intended for cases where overflow is impossible, e.g. adding small constant periods.
+func (period Period) MustAdd(other Period) Period {
+	return mustArithmetic(period.Add(other))
+}
+
 // Subtract subtracts one period from another.
+// Subtracting a period from itself always gives exactly Zero, even when the period has fractions.
 // Arithmetic overflow will result in an error.
 func (period Period) Subtract(other Period) (Period, error) {
 	return period.Add(other.Negate())
 }
 
+// MustSubtract is as per Subtract except that it panics on arithmetic overflow.
+// This is intended for cases where overflow is impossible, e.g. subtracting small constant periods.
+func (period Period) MustSubtract(other Period) Period {
+	return mustArithmetic(period.Subtract(other))
+}
+
+// AbsDiff computes the difference between two periods, as per Subtract, then makes every field
+// positive. So the result measures how far apart the two periods are, regardless of direction,
+// e.g. the absolute difference between "P1M1D" and "P2M" is "P1M1D" (not "P1M-1D").
+// Arithmetic overflow will result in an error.
+func (period Period) AbsDiff(other Period) (Period, error) {
+	diff, err := period.Subtract(other)
+	if err != nil {
+		return diff, err
+	}
+
+	return diff.Abs().mapFields(func(field decimal.Decimal) (decimal.Decimal, error) {
+		return field.Abs(), nil
+	})
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // Mul multiplies a period by a factor. Obviously, this can both enlarge and shrink it,
@@ -137,7 +222,58 @@ func (period Period) Mul(factor decimal.Decimal) (Period, error) {
 		neg:     period.neg,
 	}
 
-	return result.normaliseSign(), errors.Join(e1, e2, e3, e4, e5, e6, e7)
+	return result.NormaliseSign(), errors.Join(e1, e2, e3, e4, e5, e6, e7)
+}
+
+// MustMul is as per Mul except that it panics on arithmetic overflow.
+// This is intended for cases where overflow is impossible, e.g. multiplying small constant periods.
+func (period Period) MustMul(factor decimal.Decimal) Period {
+	return mustArithmetic(period.Mul(factor))
+}
+
+func mustArithmetic(period Period, err error) Period {
+	if err != nil {
+		panic(err)
+	}
+	return period
+}
+
+// ScaleRatio multiplies a period by the ratio num/denom, e.g. ScaleRatio(1, 2) halves it.
+// Each field is multiplied by num before being divided by denom, so the result is as exact as
+// possible; recurring fractions are rounded to the available precision. The result is not normalised.
+//
+// An error arises if denom is zero or if there is arithmetic overflow.
+func (period Period) ScaleRatio(num, denom int) (Period, error) {
+	if denom == 0 {
+		return period, errors.New("cannot scale a period by a ratio with a zero denominator")
+	}
+
+	n := decimal.MustNew(int64(num), 0)
+	d := decimal.MustNew(int64(denom), 0)
+
+	return period.mapFields(func(field decimal.Decimal) (decimal.Decimal, error) {
+		product, err := field.Mul(n)
+		if err != nil {
+			return field, err
+		}
+		return product.Quo(d)
+	})
+}
+
+// mapFields applies fn to every non-zero field, keeping the overall sign. The results are trimmed.
+func (period Period) mapFields(fn func(decimal.Decimal) (decimal.Decimal, error)) (Period, error) {
+	fields := []*decimal.Decimal{&period.years, &period.months, &period.weeks, &period.days, &period.hours, &period.minutes, &period.seconds}
+	errs := make([]error, 0, len(fields))
+
+	for _, f := range fields {
+		if f.Coef() != 0 {
+			v, err := fn(*f)
+			*f = v.Trim(0)
+			errs = append(errs, err)
+		}
+	}
+
+	return period.NormaliseSign(), errors.Join(errs...)
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -170,6 +306,105 @@ func (period Period) TotalMonthsApprox() int {
 
 //-------------------------------------------------------------------------------------------------
 
+// Compare returns -1, 0 or +1 depending on whether the period is shorter than, equal to or longer
+// than the other period.
+//
+// The comparison is exact when both periods use only years and months, or both use only weeks and
+// days, or both use only hours, minutes and seconds. For example, "PT3600S" equals "PT1H" and "P1Y"
+// equals "P12M". Otherwise, the comparison is approximate: it assumes that all days are 24 hours
+// and every year is 365.2425 days, as per Gregorian calendar rules, so "P1D" equals "PT24H" but
+// "P1M" is longer than "P30D".
+func (period Period) Compare(other Period) int {
+	a, err1 := period.totals()
+	b, err2 := other.totals()
+
+	if err1 == nil && err2 == nil {
+		switch {
+		case a.onlyMonths() && b.onlyMonths():
+			return a.months.Cmp(b.months)
+		case a.onlyDays() && b.onlyDays():
+			return a.days.Cmp(b.days)
+		case a.onlySeconds() && b.onlySeconds():
+			return a.seconds.Cmp(b.seconds)
+		}
+
+		x, err1 := a.approxSeconds()
+		y, err2 := b.approxSeconds()
+		if err1 == nil && err2 == nil {
+			return x.Cmp(y)
+		}
+	}
+
+	// too big for decimal arithmetic
+	return compareDurations(period.DurationApprox(), other.DurationApprox())
+}
+
+// fieldTotals holds the signed total months, days and seconds in a period.
+type fieldTotals struct {
+	months, days, seconds decimal.Decimal
+}
+
+func (period Period) totals() (t fieldTotals, err error) {
+	t.months, err = addMultiple(period.GetField(Month), period.GetField(Year), twelve)
+	if err != nil {
+		return t, err
+	}
+
+	t.days, err = addMultiple(period.GetField(Day), period.GetField(Week), seven)
+	if err != nil {
+		return t, err
+	}
+
+	t.seconds, err = addMultiple(period.GetField(Second), period.GetField(Minute), sixty)
+	if err != nil {
+		return t, err
+	}
+
+	t.seconds, err = addMultiple(t.seconds, period.GetField(Hour), threeSixHundred)
+	return t, err
+}
+
+func (t fieldTotals) onlyMonths() bool {
+	return t.days.IsZero() && t.seconds.IsZero()
+}
+
+func (t fieldTotals) onlyDays() bool {
+	return t.months.IsZero() && t.seconds.IsZero()
+}
+
+func (t fieldTotals) onlySeconds() bool {
+	return t.months.IsZero() && t.days.IsZero()
+}
+
+func (t fieldTotals) approxSeconds() (decimal.Decimal, error) {
+	days, err := addMultiple(t.days, t.months, daysPerMonth)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return addMultiple(t.seconds, days, secondsPerDayDecimal)
+}
+
+// addMultiple returns smaller + larger * factor.
+func addMultiple(smaller, larger, factor decimal.Decimal) (decimal.Decimal, error) {
+	product, err := larger.Mul(factor)
+	if err != nil {
+		return smaller, err
+	}
+	return smaller.Add(product)
+}
+
+func compareDurations(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+//-------------------------------------------------------------------------------------------------
+
 // DurationApprox converts a period to the equivalent duration in nanoseconds.
 // When the period specifies hours, minutes and seconds only, the result is precise.
 // however, when the period specifies years, months, weeks and days, it is impossible to
@@ -207,6 +442,121 @@ func (period Period) Duration() (time.Duration, bool) {
 	return sign * (ymwd + hms), ymwd == 0 && ok1 && ok2
 }
 
+// Nanoseconds returns the exact number of nanoseconds in a period that has only hours, minutes
+// and seconds. This is the period equivalent of time.Duration.Nanoseconds. Any fraction of a
+// nanosecond is truncated.
+//
+// An error arises if the period contains years, months, weeks or days (because their durations
+// are imprecise; see Duration), or if the result would overflow an int64.
+func (period Period) Nanoseconds() (int64, error) {
+	return period.clockUnits(nanosecondsPerSecond, "nanoseconds")
+}
+
+// ToMicroseconds returns the number of microseconds in a period that has only hours, minutes
+// and seconds. Any fraction of a microsecond is truncated. Errors arise as for Nanoseconds.
+func (period Period) ToMicroseconds() (int64, error) {
+	return period.clockUnits(microsecondsPerSecond, "microseconds")
+}
+
+// ToMilliseconds returns the number of milliseconds in a period that has only hours, minutes
+// and seconds, as used by JavaScript and many databases. Any fraction of a millisecond is
+// truncated. Errors arise as for Nanoseconds.
+func (period Period) ToMilliseconds() (int64, error) {
+	return period.clockUnits(millisecondsPerSecond, "milliseconds")
+}
+
+func (period Period) clockUnits(perSecond decimal.Decimal, unitName string) (int64, error) {
+	if !zeroCalendarValues(period) {
+		return 0, fmt.Errorf("%s has calendar fields so its %s are imprecise", period, unitName)
+	}
+
+	t, err := period.totals()
+	if err != nil {
+		return 0, err
+	}
+
+	units, err := t.seconds.Mul(perSecond)
+	if err != nil {
+		return 0, err
+	}
+
+	n, _, ok := units.Trunc(0).Int64(0)
+	if !ok {
+		return 0, fmt.Errorf("%s is too large to be expressed in %s", period, unitName)
+	}
+	return n, nil
+}
+
+// ToTimeSpec returns the period as the seconds and nanoseconds of a POSIX timespec, for use with
+// cgo, Linux timerfd or network protocols that use POSIX timestamps. As with timespec, the
+// nanoseconds are always in the range 0 to 999,999,999, so negative periods have a negative
+// number of seconds and a positive number of nanoseconds, e.g. "-PT1.25S" gives -2s + 750,000,000ns.
+// Any fraction of a nanosecond is rounded down.
+//
+// Errors arise as for Nanoseconds, except that the range is much wider.
+func (period Period) ToTimeSpec() (int64, int32, error) {
+	seconds, nanos, err := period.secondsAndNanos("timespec", true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if nanos < 0 {
+		if seconds == math.MinInt64 {
+			return 0, 0, fmt.Errorf("%s is too large to be expressed in a timespec", period)
+		}
+		seconds--
+		nanos += int64(time.Second)
+	}
+	return seconds, int32(nanos), nil
+}
+
+// ToProtoSeconds returns the period as the seconds and nanoseconds of a google.protobuf.Duration,
+// so that proto messages can be populated without importing the protobuf packages. As required by
+// that type, both values have the same sign, e.g. "-PT1.25S" gives -1s + -250,000,000ns. Any
+// fraction of a nanosecond is truncated.
+//
+// Errors arise as for Nanoseconds, except that the range is much wider.
+func (period Period) ToProtoSeconds() (int64, int32, error) {
+	seconds, nanos, err := period.secondsAndNanos("protobuf duration", false)
+	return seconds, int32(nanos), err
+}
+
+func (period Period) secondsAndNanos(what string, floor bool) (int64, int64, error) {
+	if !zeroCalendarValues(period) {
+		return 0, 0, fmt.Errorf("%s has calendar fields so it cannot be expressed precisely as a %s", period, what)
+	}
+
+	t, err := period.totals()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s := t.seconds.Trunc(9)
+	if floor {
+		s = t.seconds.Floor(9)
+	}
+
+	seconds, nanos, ok := s.Int64(9)
+	if !ok {
+		return 0, 0, fmt.Errorf("%s is too large to be expressed in a %s", period, what)
+	}
+	return seconds, nanos, nil
+}
+
+// DurationContext converts a period to the equivalent duration in nanoseconds when the period
+// starts at time t. Unlike Duration, the lengths of the actual years, months and days (including
+// daylight-saving changes in t's location) are taken into account, so this is the most accurate
+// conversion for periods that contain calendar fields. The period is added to t using AddTo.
+//
+// A flag is also returned that is true when the conversion was precise, and false otherwise,
+// i.e. when years, months, weeks or days contain fractions.
+//
+// Note that time.Duration is limited to the range 1 nanosecond to about 292 years maximum.
+func (period Period) DurationContext(t time.Time) (time.Duration, bool) {
+	end, precise := period.AddTo(t)
+	return end.Sub(t), precise
+}
+
 func totalDaysApproxE9(period Period) (int64, bool) {
 	dd, okd := fieldDuration(period.days, 1e9)
 	ww, okw := fieldDuration(period.weeks, 7*1e9)
@@ -74,6 +74,64 @@ func Test_Add_Subtract(t *testing.T) {
 	}
 }
 
+func Test_AddRaw(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		one, two, sum ISOString
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"P1Y", "P12M", "P1Y12M"},
+		{"PT16M40S", "PT1000S", "PT16M1040S"},
+		{"P6D", "P1D", "P7D"},
+		{"P1Y", "-P2Y", "-P1Y"},
+		{"P1M", "-P2M1D", "-P1M1D"},
+		{"P1Y1M", "-P1Y1M", "P0D"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.one, c.two), func(t *testing.T) {
+			s, err := MustParse(c.one).AddRaw(MustParse(c.two))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(MustParse(c.sum)), info(i, "%s + %s = %s", c.one, c.two, s))
+		})
+	}
+
+	// only the least significant field may have a fraction
+	s, err := MustParse("P1.5Y").AddRaw(MustParse("P1M"))
+	g.Expect(err).To(MatchError("only the least significant field can have a fraction; found Y fractions in P1.5Y1M"))
+	g.Expect(s.String()).To(Equal("P1.5Y1M"))
+
+	s, err = MustParse("P1Y").AddRaw(MustParse("P1.5M"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(MustParse("P1Y1.5M")))
+}
+
+func Test_AbsDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"P3D", "P1D", "P2D"},
+		{"P1D", "P3D", "P2D"},
+		{"P2M", "P1M1D", "P1M1D"},
+		{"P1M1D", "P2M", "P1M1D"},
+		{"PT1H", "-PT30M", "PT1H30M"},
+		{"-P1Y", "P1Y", "P2Y"},
+		{"P1Y2M3DT4H5M6S", "P1Y2M3DT4H5M6S", "P0D"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.a, c.b), func(t *testing.T) {
+			p, err := MustParse(c.a).AbsDiff(MustParse(c.b))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.a, c.b))
+		})
+	}
+}
+
 func Test_AddTo(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -145,6 +203,75 @@ func Test_AddTo(t *testing.T) {
 	}
 }
 
+func Test_DurationBetween(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := utc(2024, 1, 31, 10, 0, 0, 0)
+
+	cases := []struct {
+		value    string
+		t2       time.Time
+		expected time.Duration
+		precise  bool
+	}{
+		{value: "P0D", t2: t0, expected: 0, precise: true},
+		{value: "PT1H", t2: t0, expected: -time.Hour, precise: true},
+		{value: "PT1H30M", t2: t0.Add(2 * time.Hour), expected: 30 * time.Minute, precise: true},
+		{value: "P1D", t2: utc(2024, 2, 1, 10, 0, 0, 0), expected: 0, precise: true},
+		{value: "P1W1D", t2: utc(2024, 2, 8, 10, 0, 0, 0), expected: 0, precise: true},
+		{value: "P1M", t2: utc(2024, 3, 2, 10, 0, 0, 0), expected: 0, precise: true}, // 31st Jan + 1 month = 2nd March
+		{value: "P1Y1M", t2: utc(2025, 3, 3, 10, 0, 0, 0), expected: 0, precise: true},
+		{value: "P1DT-1S", t2: utc(2024, 2, 1, 10, 0, 0, 0), expected: time.Second, precise: true},
+		{value: "-P1D", t2: utc(2024, 1, 30, 12, 0, 0, 0), expected: 2 * time.Hour, precise: true},
+		{value: "P0.5D", t2: t0.Add(12 * time.Hour), expected: 0, precise: false},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			d, prec := MustParse(c.value).DurationBetween(t0, c.t2)
+			g.Expect(d).To(Equal(c.expected), info(i, c.value))
+			g.Expect(prec).To(Equal(c.precise), info(i, c.value))
+		})
+	}
+}
+
+func Test_Compare(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"P0D", "P0D", 0},
+		{"P0D", "PT1S", -1},
+		{"-PT1S", "P0D", -1},
+		// exact cases
+		{"PT3600S", "PT1H", 0},
+		{"PT3599.9S", "PT1H", -1},
+		{"PT1H-1S", "PT59M59S", 0},
+		{"P1Y", "P12M", 0},
+		{"P1Y1M", "P12M", 1},
+		{"P1W", "P7D", 0},
+		{"P1W", "P8D", -1},
+		{"-P1Y", "-P11M", -1},
+		// approximate cases
+		{"P1D", "PT24H", 0},
+		{"P1D", "PT23H", 1},
+		{"P1M", "P30D", 1},
+		{"P1M", "P31D", -1},
+		{"P1Y", "P365D", 1},
+		{"P1Y", "P366D", -1},
+		{"P1YT1S", "P1Y", 1},
+		{"-P1M", "-P30D", -1},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.a, c.b), func(t *testing.T) {
+			a, b := MustParse(c.a), MustParse(c.b)
+			g.Expect(a.Compare(b)).To(Equal(c.expected), info(i, c.a, c.b))
+			g.Expect(b.Compare(a)).To(Equal(-c.expected), info(i, c.b, c.a))
+		})
+	}
+}
+
 func Test_Mul(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -216,6 +343,68 @@ func Test_Mul_errors(t *testing.T) {
 	}
 }
 
+func Test_MustAdd_MustSubtract_MustMul(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := MustParse("P1M1D")
+	g.Expect(p.MustAdd(MustParse("PT1H"))).To(Equal(MustParse("P1M1DT1H")))
+	g.Expect(p.MustSubtract(MustParse("P1D"))).To(Equal(MustParse("P1M")))
+	g.Expect(p.MustMul(decI(3))).To(Equal(MustParse("P3M3D")))
+
+	huge := Period{years: dec(math.MaxInt64, 0)}
+	g.Expect(func() { huge.MustAdd(huge) }).To(Panic())
+	g.Expect(func() { huge.MustSubtract(huge.Negate()) }).To(Panic())
+	g.Expect(func() { huge.MustMul(decI(2)) }).To(Panic())
+}
+
+func Test_Subtract_self_is_Zero(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for i, v := range []string{"P0D", "P1Y", "-P1.5M", "P1Y-1M", "P2W3.25D", "-PT1H2M3.004S", "P1Y2M3W4DT5H6M7S"} {
+		p := MustParse(v)
+
+		d, err := p.Subtract(p)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(d == Zero).To(BeTrue(), info(i, v))
+		g.Expect(p.MustSubtract(p) == Zero).To(BeTrue(), info(i, v))
+		g.Expect(d.IsZero()).To(BeTrue(), info(i, v))
+		g.Expect(d.IsNegative()).To(BeFalse(), info(i, v))
+	}
+}
+
+func Test_ScaleRatio(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input      ISOString
+		num, denom int
+		expected   ISOString
+	}{
+		{input: "P0D", num: 1, denom: 2, expected: "P0D"},
+		{input: "P1D", num: 1, denom: 2, expected: "P0.5D"},
+		{input: "P2Y4M", num: 1, denom: 2, expected: "P1Y2M"},
+		{input: "PT1H", num: 3, denom: 4, expected: "PT0.75H"},
+		{input: "PT1H", num: 1, denom: 3, expected: "PT0.3333333333333333333H"},
+		{input: "PT3M", num: 1, denom: 3, expected: "PT1M"},
+		{input: "P1Y2M3W4DT5H6M7S", num: 2, denom: 1, expected: "P2Y4M6W8DT10H12M14S"},
+		{input: "P1D", num: -3, denom: 2, expected: "-P1.5D"},
+		{input: "-P1D", num: 3, denom: -2, expected: "P1.5D"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.input), func(t *testing.T) {
+			s, err := MustParse(c.input).ScaleRatio(c.num, c.denom)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(MustParse(c.expected)), info(i, "%s * %d/%d -> %s", c.input, c.num, c.denom, c.expected))
+		})
+	}
+
+	_, err := MustParse("P1D").ScaleRatio(1, 0)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = Period{days: dec(math.MaxInt64, 0)}.ScaleRatio(math.MaxInt64, 1)
+	g.Expect(err).To(HaveOccurred())
+}
+
 //-------------------------------------------------------------------------------------------------
 
 func Test_TotalDaysApprox(t *testing.T) {
@@ -356,3 +545,159 @@ func testPeriodToDuration(t *testing.T, i int, value string, duration time.Durat
 	d2 := pp.DurationApprox()
 	g.Expect(d2).To(Equal(duration), hint)
 }
+
+func Test_DurationContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const day = 24 * time.Hour
+
+	cases := []struct {
+		value    string
+		t0       time.Time
+		expected time.Duration
+		precise  bool
+	}{
+		{"P0D", utc(2024, 1, 1, 0, 0, 0, 0), 0, true},
+		{"PT1H30M", utc(2024, 1, 1, 0, 0, 0, 0), 90 * time.Minute, true},
+		{"P1M", utc(2024, 1, 1, 0, 0, 0, 0), 31 * day, true},
+		{"P1M", utc(2024, 2, 1, 0, 0, 0, 0), 29 * day, true},
+		{"P1M", utc(2023, 2, 1, 0, 0, 0, 0), 28 * day, true},
+		{"P1Y", utc(2024, 1, 1, 0, 0, 0, 0), 366 * day, true},
+		{"P1Y", utc(2023, 1, 1, 0, 0, 0, 0), 365 * day, true},
+		{"-P1M", utc(2024, 3, 1, 0, 0, 0, 0), -29 * day, true},
+		{"P1D", bst(2024, 3, 30, 12, 0, 0, 0), 23 * time.Hour, true},  // clocks go forward
+		{"P1D", bst(2024, 10, 26, 12, 0, 0, 0), 25 * time.Hour, true}, // clocks go back
+		{"P0.5D", utc(2024, 1, 1, 0, 0, 0, 0), 12 * time.Hour, false},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			d, prec := MustParse(c.value).DurationContext(c.t0)
+			g.Expect(d).To(Equal(c.expected), info(i, c.value))
+			g.Expect(prec).To(Equal(c.precise), info(i, c.value))
+		})
+	}
+}
+
+func Test_Nanoseconds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected int64
+	}{
+		{"P0D", 0},
+		{"PT1S", int64(time.Second)},
+		{"PT0.000000001S", 1},
+		{"PT0.0000000019S", 1},
+		{"PT1H2M3.5S", int64(time.Hour + 2*time.Minute + 3500*time.Millisecond)},
+		{"PT1H-1S", int64(59*time.Minute + 59*time.Second)},
+		{"-PT1.5M", -int64(90 * time.Second)},
+		{"PT2562047H", int64(2562047 * time.Hour)},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			n, err := MustParse(c.value).Nanoseconds()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(n).To(Equal(c.expected), info(i, c.value))
+		})
+	}
+
+	_, err := MustParse("P1DT1H").Nanoseconds()
+	g.Expect(err).To(MatchError("P1DT1H has calendar fields so its nanoseconds are imprecise"))
+
+	_, err = MustParse("PT2562048H").Nanoseconds()
+	g.Expect(err).To(MatchError("PT2562048H is too large to be expressed in nanoseconds"))
+}
+
+func Test_ToMicroseconds_ToMilliseconds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value        string
+		micro, milli int64
+	}{
+		{"P0D", 0, 0},
+		{"PT1S", 1000000, 1000},
+		{"PT0.0015S", 1500, 1},
+		{"PT1H2M3.5S", 3723500000, 3723500},
+		{"-PT1.5M", -90000000, -90000},
+		{"PT0.0000009S", 0, 0},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := MustParse(c.value)
+
+			us, err := p.ToMicroseconds()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(us).To(Equal(c.micro), info(i, c.value))
+
+			ms, err := p.ToMilliseconds()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ms).To(Equal(c.milli), info(i, c.value))
+		})
+	}
+
+	_, err := MustParse("P1M").ToMilliseconds()
+	g.Expect(err).To(MatchError("P1M has calendar fields so its milliseconds are imprecise"))
+
+	_, err = MustParse("P1W").ToMicroseconds()
+	g.Expect(err).To(MatchError("P1W has calendar fields so its microseconds are imprecise"))
+}
+
+func Test_ToTimeSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value   string
+		seconds int64
+		nanos   int32
+	}{
+		{"P0D", 0, 0},
+		{"PT1.25S", 1, 250000000},
+		{"-PT1.25S", -2, 750000000},
+		{"-PT1S", -1, 0},
+		{"PT0.0000000019S", 0, 1},
+		{"-PT0.0000000019S", -1, 999999998},
+		{"PT1H2M3.5S", 3723, 500000000},
+		{"PT100000000H", 360000000000, 0},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			s, ns, err := MustParse(c.value).ToTimeSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.seconds), info(i, c.value))
+			g.Expect(ns).To(Equal(c.nanos), info(i, c.value))
+		})
+	}
+
+	_, _, err := MustParse("P1DT1H").ToTimeSpec()
+	g.Expect(err).To(MatchError("P1DT1H has calendar fields so it cannot be expressed precisely as a timespec"))
+}
+
+func Test_ToProtoSeconds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value   string
+		seconds int64
+		nanos   int32
+	}{
+		{"P0D", 0, 0},
+		{"PT1.25S", 1, 250000000},
+		{"-PT1.25S", -1, -250000000},
+		{"-PT0.0000000019S", 0, -1},
+		{"PT1H2M3.5S", 3723, 500000000},
+		{"-PT1M", -60, 0},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			s, ns, err := MustParse(c.value).ToProtoSeconds()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.seconds), info(i, c.value))
+			g.Expect(ns).To(Equal(c.nanos), info(i, c.value))
+		})
+	}
+
+	_, _, err := MustParse("P1Y").ToProtoSeconds()
+	g.Expect(err).To(MatchError("P1Y has calendar fields so it cannot be expressed precisely as a protobuf duration"))
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+const benchmarkInput = "P2Y3M4W5DT6H7M8.9S"
+
+var (
+	benchmarkPeriod = MustParse(benchmarkInput)
+	benchmarkTime   = time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC)
+
+	// sinks prevent the compiler from eliminating the benchmarked calls
+	periodSink   Period
+	stringSink   string
+	timeSink     time.Time
+	durationSink time.Duration
+)
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		periodSink, _ = Parse(benchmarkInput)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		stringSink = benchmarkPeriod.String()
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkPeriod.WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkAddTo(b *testing.B) {
+	p := MustParse("P2Y3M4W5DT6H7M8S") // AddTo is only precise for whole numbers of days etc
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		timeSink, _ = p.AddTo(benchmarkTime)
+	}
+}
+
+func BenchmarkDuration(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		durationSink, _ = benchmarkPeriod.Duration()
+	}
+}
+
+func BenchmarkNormalise(b *testing.B) {
+	p := MustParse("P25M16DT50H130M125.5S")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		periodSink = p.Normalise(false)
+	}
+}
+
+func BenchmarkBetween(b *testing.B) {
+	t2 := benchmarkTime.AddDate(2, 3, 33).Add(6*time.Hour + 7*time.Minute)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		periodSink = Between(benchmarkTime, t2)
+	}
+}
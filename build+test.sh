@@ -22,8 +22,12 @@ fi
 echo period...
 v go test -v -covermode=count -coverprofile=period.out .
 v go tool cover -func=period.out
+v go test -tags periodpool .
 #[ -z "$COVERALLS_TOKEN" ] || goveralls -coverprofile=period.out -service=travis-ci -repotoken $COVERALLS_TOKEN
 
+echo cbor...
+(cd cbor && v go test . && v go vet .)
+
 v gofmt -l -w *.go
 
 v go vet ./...
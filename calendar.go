@@ -0,0 +1,424 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/govalues/decimal"
+)
+
+// BusinessCalendar decides which days are workdays, e.g. according to the weekends and
+// public holidays of some organisation.
+type BusinessCalendar interface {
+	IsWorkday(time.Time) bool
+}
+
+// Weekdays is a BusinessCalendar in which Monday to Friday are workdays; it has no holidays.
+var Weekdays BusinessCalendar = weekdays{}
+
+type weekdays struct{}
+
+func (weekdays) IsWorkday(t time.Time) bool {
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// maxBusinessDaysSpan limits how many days BusinessDays will test one by one. It is the number
+// of days in 400 Gregorian years.
+const maxBusinessDaysSpan = 146097
+
+// BusinessDays counts the workdays in the period when it starts at a given time. Each day
+// from start (inclusive) up to the end of the period (exclusive) is tested using cal. The
+// end is found using AddTo, so the count is only approximate if the period contains
+// fractional years, months, weeks or days. For negative periods, the days before start
+// are counted and the result is negative.
+//
+// The count is necessarily inexact for periods containing years or months, whose length in
+// days depends on the calendar: for example, "P1M" from 1st February spans fewer workdays than
+// from 1st March.
+//
+// With the Weekdays calendar, whole weeks are counted arithmetically, so any period can be
+// used. With other calendars, every day is tested, so a panic arises if the period spans more
+// than 400 years.
+func (period Period) BusinessDays(start time.Time, cal BusinessCalendar) int {
+	end, _ := period.AddTo(start)
+
+	days := (dateOf(end, start.Location()).Unix() - dateOf(start, start.Location()).Unix()) / secondsPerDay
+	if days < 0 {
+		days = -days
+	}
+
+	count, skip := 0, 0
+	if cal == Weekdays && days > 7 {
+		// every whole week has five weekdays; the final week is still tested day by day
+		weeks := int(days/7) - 1
+		count, skip = 5*weeks, 7*weeks
+	} else if days > maxBusinessDaysSpan {
+		panic(fmt.Sprintf("%s spans too many days to count business days", period))
+	}
+
+	if period.IsNegative() {
+		for t := start.AddDate(0, 0, -1-skip); !t.Before(end); t = t.AddDate(0, 0, -1) {
+			if cal.IsWorkday(t) {
+				count++
+			}
+		}
+		return -count
+	}
+
+	for t := start.AddDate(0, 0, skip); t.Before(end); t = t.AddDate(0, 0, 1) {
+		if cal.IsWorkday(t) {
+			count++
+		}
+	}
+	return count
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Age computes the calendar age of someone born on birthdate, as at the current date. The result
+// contains whole years, months and days, e.g. "P42Y3M11D". Only the dates matter: the clock times
+// are disregarded and now is expressed in the birthdate's location. Months are counted such that
+// someone born on the last day of a long month (or on 29th February) gains a month (or a year)
+// on the last day of a shorter month.
+//
+// If birthdate is in the future, the result is a negative period.
+func Age(birthdate time.Time) Period {
+	return ageAt(birthdate, time.Now())
+}
+
+func ageAt(birthdate, now time.Time) Period {
+	from := dateOf(birthdate, birthdate.Location())
+	to := dateOf(now, birthdate.Location())
+	if to.Before(from) {
+		return ageAt(now, birthdate).Negate()
+	}
+
+	months := wholeMonths(from, to)
+	anchor := addMonthsClamped(from, months)
+	days := int(to.Sub(anchor) / (24 * time.Hour))
+
+	return NewYMD(months/12, months%12, days)
+}
+
+// wholeMonths counts the complete months from one UTC date to a later one.
+func wholeMonths(from, to time.Time) int {
+	y1, m1, _ := from.Date()
+	y2, m2, _ := to.Date()
+
+	months := (y2-y1)*12 + int(m2) - int(m1)
+	if addMonthsClamped(from, months).After(to) {
+		months--
+	}
+	return months
+}
+
+// addMonthsClamped adds some months to a UTC date, limiting the day to the end of the month,
+// so that e.g. 31st January plus one month gives the last day of February.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	last := time.Date(y, m+time.Month(months)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(y, m+time.Month(months), min(d, last), 0, 0, 0, 0, time.UTC)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// StartOf returns the first instant of the year, month, week, day, hour, minute or second that
+// contains t, in t's location. Weeks start on Monday, as per ISO-8601. For example,
+// StartOf(t, Month) gives midnight on the first day of t's month.
+//
+// Note that this is not a method on Period; it is a calendar utility for use alongside AddTo.
+//
+// A panic arises if the unit is unknown.
+func StartOf(t time.Time, unit Designator) time.Time {
+	y, m, d := t.Date()
+	hh, mm, ss := t.Clock()
+	loc := t.Location()
+
+	switch unit {
+	case Year:
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+	case Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	case Week:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-daysSinceMonday, 0, 0, 0, 0, loc)
+	case Day:
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	case Hour:
+		return time.Date(y, m, d, hh, 0, 0, 0, loc)
+	case Minute:
+		return time.Date(y, m, d, hh, mm, 0, 0, loc)
+	case Second:
+		return time.Date(y, m, d, hh, mm, ss, 0, loc)
+	}
+
+	panic(unit)
+}
+
+// EndOf returns the last instant (to the nearest nanosecond) of the year, month, week, day, hour,
+// minute or second that contains t, in t's location. It is one nanosecond before the start of
+// the following unit.
+//
+// A panic arises if the unit is unknown.
+func EndOf(t time.Time, unit Designator) time.Time {
+	start := StartOf(t, unit)
+	y, m, d := start.Date()
+	hh, mm, ss := start.Clock()
+	loc := start.Location()
+
+	var next time.Time
+	switch unit {
+	case Year:
+		next = time.Date(y+1, m, d, 0, 0, 0, 0, loc)
+	case Month:
+		next = time.Date(y, m+1, d, 0, 0, 0, 0, loc)
+	case Week:
+		next = time.Date(y, m, d+7, 0, 0, 0, 0, loc)
+	case Day:
+		next = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	case Hour:
+		next = time.Date(y, m, d, hh+1, 0, 0, 0, loc)
+	case Minute:
+		next = time.Date(y, m, d, hh, mm+1, 0, 0, loc)
+	case Second:
+		next = time.Date(y, m, d, hh, mm, ss+1, 0, loc)
+	}
+
+	return next.Add(-time.Nanosecond)
+}
+
+// unixMonday is the Monday on which ISO weeks are counted for Align; it is close to the Unix epoch.
+var unixMonday = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// Align rounds t down to the most recent boundary that is aligned to the period's value in the
+// given unit, which is typically a time such as time.Now(). For example, for "P1M" the result is
+// the start of t's month, for "P3M" it is the start of t's quarter and for "PT15M" it is the
+// start of t's quarter-hour. This is useful for generating aligned time series data.
+//
+// The boundaries are counted from a fixed origin in t's location: years from year zero, months
+// from January of year zero, weeks from Monday 5th January 1970, days from 1st January 1970, hours
+// from midnight, minutes from the start of the hour and seconds from the start of the minute. So
+// hours, minutes and seconds align best when the period's value divides 24 or 60 exactly.
+//
+// If the period's value in the unit is not a positive whole number, the result is StartOf(t, unit).
+//
+// A panic arises if the unit is unknown.
+func (period Period) Align(t time.Time, unit Designator) time.Time {
+	start := StartOf(t, unit)
+
+	step := 1
+	if v := period.GetField(unit); v.IsInt() && v.Sign() > 0 {
+		n, _, ok := v.Int64(0)
+		if ok && n <= math.MaxInt {
+			step = int(n)
+		}
+	}
+	if step == 1 {
+		return start
+	}
+
+	y, m, d := start.Date()
+	hh, mm, ss := start.Clock()
+	loc := start.Location()
+
+	switch unit {
+	case Year:
+		return time.Date(floorMultiple(y, step), time.January, 1, 0, 0, 0, 0, loc)
+	case Month:
+		months := floorMultiple(y*12+int(m)-1, step)
+		return time.Date(0, time.Month(months+1), 1, 0, 0, 0, 0, loc)
+	case Week:
+		weeks := DaysBetween(unixMonday, dateOf(start, loc)) / 7
+		return time.Date(y, m, d-7*(weeks-floorMultiple(weeks, step)), 0, 0, 0, 0, loc)
+	case Day:
+		days := DaysBetween(unixMonday, dateOf(start, loc)) + 4
+		return time.Date(y, m, d-(days-floorMultiple(days, step)), 0, 0, 0, 0, loc)
+	case Hour:
+		return time.Date(y, m, d, floorMultiple(hh, step), 0, 0, 0, loc)
+	case Minute:
+		return time.Date(y, m, d, hh, floorMultiple(mm, step), 0, 0, loc)
+	default: // Second
+		return time.Date(y, m, d, hh, mm, floorMultiple(ss, step), 0, loc)
+	}
+}
+
+// floorMultiple rounds x down to a multiple of n, including when x is negative.
+func floorMultiple(x, n int) int {
+	r := x % n
+	if r < 0 {
+		r += n
+	}
+	return x - r
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// IsExpired returns true if the period has fully elapsed since from, i.e. if from plus the period
+// is before the current time. The period is converted using DurationApprox, so calendar fields
+// are approximated; see IsExpiredExact for a precise alternative.
+func (period Period) IsExpired(from time.Time) bool {
+	return from.Add(period.DurationApprox()).Before(time.Now())
+}
+
+// IsExpiredExact returns true if the period has fully elapsed since from. Unlike IsExpired, the
+// end of the period is found using AddTo, so the actual lengths of years, months and days are
+// taken into account. A second flag is also returned that is true when the end was computed
+// precisely, as per AddTo.
+func (period Period) IsExpiredExact(from time.Time) (expired, precise bool) {
+	end, precise := period.AddTo(from)
+	return end.Before(time.Now()), precise
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// DaysBetween counts the calendar days from t1 to t2, i.e. the number of midnights crossed,
+// regardless of the clock times. For example, 23:00 on one day to 01:00 on the next is one day.
+// The dates are taken in t1's location, so that daylight-saving changes do not matter; use
+// t1.In(loc) to count days in some other location, e.g. time.UTC. The result is negative if t2
+// is on an earlier date than t1.
+//
+// Note that this is not a method on Period; compare with Between(t1, t2).DaysIncWeeks(), which
+// counts whole 24-hour days.
+func DaysBetween(t1, t2 time.Time) int {
+	from := dateOf(t1, t1.Location())
+	to := dateOf(t2, t1.Location())
+	return int(to.Sub(from) / (24 * time.Hour))
+}
+
+// dateOf gives midnight UTC on the date of t in loc, which allows days to be counted exactly.
+func dateOf(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthsBetween counts the complete calendar months from t1 to t2, regardless of the clock times.
+// As for Age, a month is complete on the same day of the following month or, if that month is
+// shorter, on its last day; so 31st January to 28th February is one month (29th in leap years).
+// The dates are taken in t1's location. The result is negative if t2 is on an earlier date than t1.
+func MonthsBetween(t1, t2 time.Time) int {
+	from := dateOf(t1, t1.Location())
+	to := dateOf(t2, t1.Location())
+	if to.Before(from) {
+		return -wholeMonths(to, from)
+	}
+	return wholeMonths(from, to)
+}
+
+// YearsBetween counts the complete calendar years from t1 to t2, regardless of the clock times.
+// A year is complete on the anniversary of t1's date, so 15th January 2020 to 14th January 2021 is
+// zero years but to 15th January 2021 is one year. An anniversary of 29th February falls on 28th
+// February in other years, as for Age. The dates are taken in t1's location. The result is negative
+// if t2 is on an earlier date than t1.
+func YearsBetween(t1, t2 time.Time) int {
+	return MonthsBetween(t1, t2) / 12
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// CyclesBetween counts how many complete cycles of the period fit between t1 and t2, which is the
+// period equivalent of integer division with remainder, e.g. for counting how many billing cycles
+// have elapsed. The count n is the largest for which t1 plus n times the period is not after t2;
+// the remainder is the rest of the time up to t2, as given by Between and then Normalise(true), so
+// it is never negative. If t2 is before t1, n is negative.
+//
+// The end of n cycles is computed by multiplying the period by n and adding it to t1 with AddTo,
+// rather than by adding the period n times, so that the cycles do not drift. For example, monthly
+// cycles from 31st January 2024 end on 2nd March and then 31st March, not 2nd April.
+//
+// An error arises if the period is not positive, or on arithmetic overflow. Because the cycles
+// are estimated using time.Duration, this includes the case where t1 and t2 are more than about
+// 292 years apart.
+func (period Period) CyclesBetween(t1, t2 time.Time) (int, Period, error) {
+	approx := period.DurationApprox()
+	if approx <= 0 {
+		return 0, Zero, fmt.Errorf("%s is not a positive period so cannot be used for cycles", period)
+	}
+
+	span := t2.Sub(t1)
+	if span == math.MaxInt64 || span == math.MinInt64 {
+		// time.Time.Sub saturates instead of overflowing
+		return 0, Zero, fmt.Errorf("%s to %s is too long a span for cycles of %s", t1, t2, period)
+	}
+
+	n := int(span / approx) // an estimate that is adjusted below
+
+	tn, err := period.cycleEnd(t1, n)
+	for err == nil && tn.After(t2) {
+		n--
+		tn, err = period.cycleEnd(t1, n)
+	}
+
+	if err != nil {
+		return 0, Zero, err
+	}
+
+	for {
+		next, e := period.cycleEnd(t1, n+1)
+		if e != nil || next.After(t2) {
+			break // an overflow here is necessarily beyond t2
+		}
+		n++
+		tn = next
+	}
+
+	return n, Between(tn, t2).Normalise(true), nil
+}
+
+// cycleEnd gives the end of n cycles of the period starting at t. The period is positive, so
+// an end on the wrong side of t shows that the duration arithmetic in AddTo has wrapped around.
+func (period Period) cycleEnd(t time.Time, n int) (time.Time, error) {
+	p, err := period.Mul(decimal.MustNew(int64(n), 0))
+	if err != nil {
+		return time.Time{}, err
+	}
+	end, _ := p.AddTo(t)
+	if (n > 0 && !end.After(t)) || (n < 0 && !end.Before(t)) {
+		return time.Time{}, fmt.Errorf("%d cycles of %s from %s overflows", n, period, t)
+	}
+	return end, nil
+}
+
+// PrevAlignment returns the latest time that is not after now and is a whole number of cycles of
+// the period from epoch, i.e. the largest epoch + n * period that is ≤ now. This and NextAlignment
+// enable cron-style scheduling. See CyclesBetween, which determines n.
+//
+// A panic arises if the period is not positive or on arithmetic overflow, which includes the case
+// where now and epoch are more than about 292 years apart.
+func (period Period) PrevAlignment(now, epoch time.Time) time.Time {
+	prev, _ := period.alignments(now, epoch)
+	return prev
+}
+
+// NextAlignment returns the earliest time that is after now and is a whole number of cycles of
+// the period from epoch, i.e. the smallest epoch + n * period that is > now. See PrevAlignment.
+//
+// A panic arises if the period is not positive or on arithmetic overflow.
+func (period Period) NextAlignment(now, epoch time.Time) time.Time {
+	_, next := period.alignments(now, epoch)
+	return next
+}
+
+func (period Period) alignments(now, epoch time.Time) (time.Time, time.Time) {
+	n, _, err := period.CyclesBetween(epoch, now)
+	if err != nil {
+		panic(err)
+	}
+
+	prev, err := period.cycleEnd(epoch, n)
+	if err != nil {
+		panic(err)
+	}
+
+	next, err := period.cycleEnd(epoch, n+1)
+	if err != nil {
+		panic(err)
+	}
+
+	return prev, next
+}
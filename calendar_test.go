@@ -0,0 +1,357 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type holidays map[time.Time]bool
+
+func (h holidays) IsWorkday(t time.Time) bool {
+	y, m, d := t.Date()
+	return Weekdays.IsWorkday(t) && !h[time.Date(y, m, d, 0, 0, 0, 0, time.UTC)]
+}
+
+func TestBusinessDays(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monday := utc(2024, 6, 3, 9, 0, 0, 0)
+	xmas := holidays{utc(2024, 12, 25, 0, 0, 0, 0): true, utc(2024, 12, 26, 0, 0, 0, 0): true}
+
+	cases := []struct {
+		period   string
+		start    time.Time
+		cal      BusinessCalendar
+		expected int
+	}{
+		{"P0D", monday, Weekdays, 0},
+		{"P1D", monday, Weekdays, 1},
+		{"P5D", monday, Weekdays, 5},
+		{"P1W", monday, Weekdays, 5},
+		{"P2W", monday, Weekdays, 10},
+		{"PT12H", monday, Weekdays, 1},
+		{"P1M", monday, Weekdays, 22},
+		{"-P1W", monday, Weekdays, -5},
+		{"-P1D", monday, Weekdays, 0}, // Sunday
+		{"-P3D", monday, Weekdays, -1},
+		{"P1W", utc(2024, 12, 23, 0, 0, 0, 0), xmas, 3},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			n := MustParse(c.period).BusinessDays(c.start, c.cal)
+			g.Expect(n).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+
+	// whole weeks of Weekdays are counted arithmetically; this matches testing each day
+	for _, p := range []string{"P3Y5D", "P1Y1M", "-P3Y5D", "-P1Y1M", "P8D", "-P8D", "P15DT1H"} {
+		g.Expect(MustParse(p).BusinessDays(monday, Weekdays)).To(Equal(MustParse(p).BusinessDays(monday, holidays{})), p)
+	}
+
+	// 100000 years is 250 Gregorian cycles of exactly 20871 weeks each
+	g.Expect(MustParse("P100000Y").BusinessDays(monday, Weekdays)).To(Equal(250 * 20871 * 5))
+
+	g.Expect(MustParse("P400Y").BusinessDays(monday, holidays{})).To(Equal(20871 * 5))
+	g.Expect(func() { MustParse("P401Y").BusinessDays(monday, holidays{}) }).To(Panic())
+}
+
+func TestAge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		birthdate, now time.Time
+		expected       string
+	}{
+		{utc(2000, 1, 15, 0, 0, 0, 0), utc(2000, 1, 15, 12, 0, 0, 0), "P0D"},
+		{utc(2000, 1, 15, 0, 0, 0, 0), utc(2024, 1, 15, 0, 0, 0, 0), "P24Y"},
+		{utc(2000, 1, 15, 0, 0, 0, 0), utc(2024, 1, 14, 0, 0, 0, 0), "P23Y11M30D"},
+		{utc(2000, 1, 31, 0, 0, 0, 0), utc(2000, 3, 1, 0, 0, 0, 0), "P1M1D"},
+		{utc(2000, 2, 29, 0, 0, 0, 0), utc(2001, 2, 28, 0, 0, 0, 0), "P1Y"},
+		{utc(2000, 2, 29, 0, 0, 0, 0), utc(2001, 2, 27, 0, 0, 0, 0), "P11M29D"},
+		{utc(2000, 2, 29, 0, 0, 0, 0), utc(2001, 3, 1, 0, 0, 0, 0), "P1Y1D"},
+		{utc(1990, 6, 20, 0, 0, 0, 0), utc(2024, 8, 5, 0, 0, 0, 0), "P34Y1M16D"},
+		{utc(2024, 8, 5, 0, 0, 0, 0), utc(1990, 6, 20, 0, 0, 0, 0), "-P34Y1M16D"},
+		{utc(2030, 1, 1, 0, 0, 0, 0), utc(2029, 12, 31, 0, 0, 0, 0), "-P1D"},
+		// one year before 29th February is 1st March according to time.AddDate
+		{utc(2024, 2, 29, 0, 0, 0, 0).AddDate(-1, 0, 0), utc(2024, 2, 29, 0, 0, 0, 0), "P11M28D"},
+		{utc(2025, 3, 1, 0, 0, 0, 0).AddDate(-1, 0, 0), utc(2025, 3, 1, 0, 0, 0, 0), "P1Y"},
+		{utc(2025, 2, 28, 0, 0, 0, 0).AddDate(-1, 0, 0), utc(2025, 2, 28, 0, 0, 0, 0), "P1Y"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.expected), func(t *testing.T) {
+			g.Expect(ageAt(c.birthdate, c.now)).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+}
+
+func TestStartOfEndOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := utc(2024, 2, 15, 13, 45, 30, 123) // a Thursday
+
+	cases := []struct {
+		unit       Designator
+		start, end time.Time
+	}{
+		{Year, utc(2024, 1, 1, 0, 0, 0, 0), utc(2025, 1, 1, 0, 0, 0, 0).Add(-time.Nanosecond)},
+		{Month, utc(2024, 2, 1, 0, 0, 0, 0), utc(2024, 3, 1, 0, 0, 0, 0).Add(-time.Nanosecond)},
+		{Week, utc(2024, 2, 12, 0, 0, 0, 0), utc(2024, 2, 19, 0, 0, 0, 0).Add(-time.Nanosecond)},
+		{Day, utc(2024, 2, 15, 0, 0, 0, 0), utc(2024, 2, 16, 0, 0, 0, 0).Add(-time.Nanosecond)},
+		{Hour, utc(2024, 2, 15, 13, 0, 0, 0), utc(2024, 2, 15, 14, 0, 0, 0).Add(-time.Nanosecond)},
+		{Minute, utc(2024, 2, 15, 13, 45, 0, 0), utc(2024, 2, 15, 13, 46, 0, 0).Add(-time.Nanosecond)},
+		{Second, utc(2024, 2, 15, 13, 45, 30, 0), utc(2024, 2, 15, 13, 45, 31, 0).Add(-time.Nanosecond)},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.unit), func(t *testing.T) {
+			g.Expect(StartOf(t0, c.unit)).To(Equal(c.start), info(i, c.unit))
+			g.Expect(EndOf(t0, c.unit)).To(Equal(c.end), info(i, c.unit))
+		})
+	}
+
+	sunday := utc(2024, 2, 18, 8, 0, 0, 0)
+	g.Expect(StartOf(sunday, Week)).To(Equal(utc(2024, 2, 12, 0, 0, 0, 0)))
+
+	g.Expect(StartOf(bst(2024, 7, 4, 10, 0, 0, 0), Day)).To(Equal(bst(2024, 7, 4, 0, 0, 0, 0)))
+
+	g.Expect(func() { StartOf(t0, Designator(0)) }).To(Panic())
+}
+
+func TestIsExpired(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Now()
+
+	g.Expect(MustParse("PT1H").IsExpired(now.Add(-2 * time.Hour))).To(BeTrue())
+	g.Expect(MustParse("PT1H").IsExpired(now.Add(-30 * time.Minute))).To(BeFalse())
+	g.Expect(MustParse("-PT1H").IsExpired(now.Add(30 * time.Minute))).To(BeTrue())
+
+	expired, precise := MustParse("P1M").IsExpiredExact(now.AddDate(0, 0, -32))
+	g.Expect(expired).To(BeTrue())
+	g.Expect(precise).To(BeTrue())
+
+	expired, precise = MustParse("P1M").IsExpiredExact(now.AddDate(0, 0, -27))
+	g.Expect(expired).To(BeFalse())
+	g.Expect(precise).To(BeTrue())
+
+	_, precise = MustParse("P0.5M").IsExpiredExact(now)
+	g.Expect(precise).To(BeFalse())
+}
+
+func TestDaysBetween(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		t1, t2   time.Time
+		expected int
+	}{
+		{utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 1, 1, 23, 59, 59, 0), 0},
+		{utc(2024, 1, 1, 23, 0, 0, 0), utc(2024, 1, 2, 1, 0, 0, 0), 1},
+		{utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 3, 1, 0, 0, 0, 0), 60},
+		{utc(2024, 1, 2, 1, 0, 0, 0), utc(2024, 1, 1, 23, 0, 0, 0), -1},
+		// spring forward: only 23 hours elapse
+		{bst(2024, 3, 30, 12, 0, 0, 0), bst(2024, 3, 31, 12, 0, 0, 0), 1},
+		{bst(2024, 3, 30, 23, 0, 0, 0), bst(2024, 3, 31, 3, 0, 0, 0), 1},
+		// fall back: 25 hours elapse
+		{bst(2024, 10, 26, 12, 0, 0, 0), bst(2024, 10, 27, 12, 0, 0, 0), 1},
+		{bst(2024, 10, 26, 0, 0, 0, 0), bst(2024, 10, 27, 23, 30, 0, 0), 1},
+		// the dates of t2 are taken in t1's location
+		{utc(2024, 1, 1, 12, 0, 0, 0), utc(2024, 1, 1, 20, 0, 0, 0).In(tokyo), 0},
+		{utc(2024, 1, 1, 12, 0, 0, 0).In(tokyo), utc(2024, 1, 1, 20, 0, 0, 0), 1},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %d", i, c.expected), func(t *testing.T) {
+			g.Expect(DaysBetween(c.t1, c.t2)).To(Equal(c.expected), info(i, "%s %s", c.t1, c.t2))
+		})
+	}
+}
+
+func TestMonthsBetween(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		t1, t2   time.Time
+		expected int
+	}{
+		{utc(2023, 1, 15, 0, 0, 0, 0), utc(2023, 1, 31, 0, 0, 0, 0), 0},
+		{utc(2023, 1, 15, 0, 0, 0, 0), utc(2023, 2, 14, 23, 0, 0, 0), 0},
+		{utc(2023, 1, 15, 12, 0, 0, 0), utc(2023, 2, 15, 1, 0, 0, 0), 1},
+		{utc(2023, 1, 31, 0, 0, 0, 0), utc(2023, 2, 28, 0, 0, 0, 0), 1},
+		{utc(2023, 1, 31, 0, 0, 0, 0), utc(2023, 2, 27, 0, 0, 0, 0), 0},
+		// leap year February
+		{utc(2024, 1, 31, 0, 0, 0, 0), utc(2024, 2, 28, 0, 0, 0, 0), 0},
+		{utc(2024, 1, 31, 0, 0, 0, 0), utc(2024, 2, 29, 0, 0, 0, 0), 1},
+		{utc(2024, 2, 29, 0, 0, 0, 0), utc(2025, 2, 28, 0, 0, 0, 0), 12},
+		// March to April (30 days)
+		{utc(2023, 3, 31, 0, 0, 0, 0), utc(2023, 4, 30, 0, 0, 0, 0), 1},
+		{utc(2023, 3, 31, 0, 0, 0, 0), utc(2023, 5, 30, 0, 0, 0, 0), 1},
+		{utc(2023, 3, 31, 0, 0, 0, 0), utc(2023, 5, 31, 0, 0, 0, 0), 2},
+		{utc(2020, 6, 10, 0, 0, 0, 0), utc(2024, 8, 9, 0, 0, 0, 0), 49},
+		{utc(2023, 3, 15, 0, 0, 0, 0), utc(2023, 1, 15, 0, 0, 0, 0), -2},
+		// daylight saving and timezone crossings
+		{bst(2024, 3, 1, 0, 30, 0, 0), bst(2024, 4, 1, 0, 30, 0, 0), 1},
+		{utc(2024, 1, 31, 20, 0, 0, 0).In(tokyo), utc(2024, 2, 29, 0, 0, 0, 0), 0},
+		{utc(2024, 1, 31, 20, 0, 0, 0), utc(2024, 2, 28, 20, 0, 0, 0).In(tokyo), 0},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %d", i, c.expected), func(t *testing.T) {
+			g.Expect(MonthsBetween(c.t1, c.t2)).To(Equal(c.expected), info(i, "%s %s", c.t1, c.t2))
+		})
+	}
+}
+
+func TestYearsBetween(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		t1, t2   time.Time
+		expected int
+	}{
+		{utc(2020, 1, 15, 0, 0, 0, 0), utc(2021, 1, 14, 23, 59, 0, 0), 0},
+		{utc(2020, 1, 15, 12, 0, 0, 0), utc(2021, 1, 15, 0, 0, 0, 0), 1},
+		{utc(2020, 1, 15, 0, 0, 0, 0), utc(2030, 6, 1, 0, 0, 0, 0), 10},
+		{utc(2020, 2, 29, 0, 0, 0, 0), utc(2021, 2, 27, 0, 0, 0, 0), 0},
+		{utc(2020, 2, 29, 0, 0, 0, 0), utc(2021, 2, 28, 0, 0, 0, 0), 1},
+		{utc(2020, 2, 29, 0, 0, 0, 0), utc(2024, 2, 28, 0, 0, 0, 0), 3},
+		{utc(2020, 2, 29, 0, 0, 0, 0), utc(2024, 2, 29, 0, 0, 0, 0), 4},
+		{utc(2021, 1, 15, 0, 0, 0, 0), utc(2020, 1, 16, 0, 0, 0, 0), 0},
+		{utc(2021, 1, 15, 0, 0, 0, 0), utc(2020, 1, 15, 0, 0, 0, 0), -1},
+		{bst(2020, 7, 1, 0, 30, 0, 0), utc(2021, 6, 30, 23, 45, 0, 0), 1},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %d", i, c.expected), func(t *testing.T) {
+			g.Expect(YearsBetween(c.t1, c.t2)).To(Equal(c.expected), info(i, "%s %s", c.t1, c.t2))
+		})
+	}
+}
+
+func TestCyclesBetween(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period    string
+		t1, t2    time.Time
+		n         int
+		remainder string
+	}{
+		{"P1M", utc(2024, 1, 15, 0, 0, 0, 0), utc(2024, 1, 20, 0, 0, 0, 0), 0, "PT120H"},
+		{"P1M", utc(2024, 1, 15, 0, 0, 0, 0), utc(2024, 4, 15, 0, 0, 0, 0), 3, "P0D"},
+		{"P1M", utc(2024, 1, 15, 0, 0, 0, 0), utc(2024, 4, 16, 6, 0, 0, 0), 3, "PT30H"},
+		{"P1M", utc(2024, 1, 31, 0, 0, 0, 0), utc(2024, 4, 30, 0, 0, 0, 0), 2, "PT720H"},
+		{"P1W", utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 12, 31, 12, 0, 0, 0), 52, "PT36H"},
+		{"PT8H", utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 1, 1, 23, 0, 0, 0), 2, "PT7H"},
+		{"P1D", utc(2024, 1, 10, 0, 0, 0, 0), utc(2024, 1, 7, 12, 0, 0, 0), -3, "PT12H"},
+		{"P1D", bst(2024, 3, 30, 12, 0, 0, 0), bst(2024, 3, 31, 12, 0, 0, 0), 1, "P0D"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			n, rem, err := MustParse(c.period).CyclesBetween(c.t1, c.t2)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(n).To(Equal(c.n), info(i, c.period))
+			g.Expect(rem).To(Equal(MustParse(c.remainder)), info(i, c.period))
+		})
+	}
+
+	_, _, err := Zero.CyclesBetween(utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 2, 1, 0, 0, 0, 0))
+	g.Expect(err).To(MatchError("P0D is not a positive period so cannot be used for cycles"))
+
+	_, _, err = MustParse("-P1D").CyclesBetween(utc(2024, 1, 1, 0, 0, 0, 0), utc(2024, 2, 1, 0, 0, 0, 0))
+	g.Expect(err).To(HaveOccurred())
+
+	// the span is too long for time.Duration; this must not hang
+	_, _, err = MustParse("PT1H").CyclesBetween(utc(1700, 1, 1, 0, 0, 0, 0), utc(2000, 1, 1, 0, 0, 0, 0))
+	g.Expect(err).To(HaveOccurred())
+
+	_, _, err = MustParse("PT1H").CyclesBetween(utc(2000, 1, 1, 0, 0, 0, 0), utc(1700, 1, 1, 0, 0, 0, 0))
+	g.Expect(err).To(HaveOccurred())
+
+	// nearly the longest span that time.Duration allows
+	n, _, err := MustParse("PT100000H").CyclesBetween(utc(1800, 1, 1, 0, 0, 0, 0), utc(2092, 1, 1, 0, 0, 0, 0))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(Equal(25))
+}
+
+func TestAlign(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := utc(2023, 8, 17, 14, 38, 27, 500) // a Thursday
+
+	cases := []struct {
+		period   string
+		unit     Designator
+		expected time.Time
+	}{
+		{"P1Y", Year, utc(2023, 1, 1, 0, 0, 0, 0)},
+		{"P10Y", Year, utc(2020, 1, 1, 0, 0, 0, 0)},
+		{"P1M", Month, utc(2023, 8, 1, 0, 0, 0, 0)},
+		{"P3M", Month, utc(2023, 7, 1, 0, 0, 0, 0)},
+		{"P6M", Month, utc(2023, 7, 1, 0, 0, 0, 0)},
+		{"P5M", Month, utc(2023, 5, 1, 0, 0, 0, 0)}, // August is 24283 months after year zero
+		{"P1W", Week, utc(2023, 8, 14, 0, 0, 0, 0)},
+		{"P2W", Week, utc(2023, 8, 7, 0, 0, 0, 0)}, // 14th August is 2797 weeks after 1970-01-05
+		{"P1D", Day, utc(2023, 8, 17, 0, 0, 0, 0)},
+		{"P7D", Day, utc(2023, 8, 17, 0, 0, 0, 0)}, // 19586 days since 1970-01-01
+		{"P10D", Day, utc(2023, 8, 11, 0, 0, 0, 0)},
+		{"PT1H", Hour, utc(2023, 8, 17, 14, 0, 0, 0)},
+		{"PT6H", Hour, utc(2023, 8, 17, 12, 0, 0, 0)},
+		{"PT15M", Minute, utc(2023, 8, 17, 14, 30, 0, 0)},
+		{"PT10S", Second, utc(2023, 8, 17, 14, 38, 20, 0)},
+		{"P1M", Day, utc(2023, 8, 17, 0, 0, 0, 0)},    // no days in the period
+		{"P1.5M", Month, utc(2023, 8, 1, 0, 0, 0, 0)}, // not whole
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			g.Expect(MustParse(c.period).Align(t0, c.unit)).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+
+	g.Expect(MustParse("P10Y").Align(utc(-15, 6, 1, 0, 0, 0, 0), Year)).To(Equal(utc(-20, 1, 1, 0, 0, 0, 0)))
+	g.Expect(MustParse("P2D").Align(utc(1969, 12, 31, 12, 0, 0, 0), Day)).To(Equal(utc(1969, 12, 30, 0, 0, 0, 0)))
+	g.Expect(MustParse("PT6H").Align(bst(2023, 8, 17, 14, 0, 0, 0), Hour)).To(Equal(bst(2023, 8, 17, 12, 0, 0, 0)))
+}
+
+func TestPrevNextAlignment(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	epoch := utc(2024, 1, 1, 9, 0, 0, 0)
+
+	cases := []struct {
+		period     string
+		now        time.Time
+		prev, next time.Time
+	}{
+		{"PT15M", utc(2024, 3, 5, 10, 7, 0, 0), utc(2024, 3, 5, 10, 0, 0, 0), utc(2024, 3, 5, 10, 15, 0, 0)},
+		{"PT15M", utc(2024, 3, 5, 10, 15, 0, 0), utc(2024, 3, 5, 10, 15, 0, 0), utc(2024, 3, 5, 10, 30, 0, 0)},
+		{"P1D", utc(2024, 3, 5, 8, 0, 0, 0), utc(2024, 3, 4, 9, 0, 0, 0), utc(2024, 3, 5, 9, 0, 0, 0)},
+		{"P1W", utc(2024, 3, 5, 8, 0, 0, 0), utc(2024, 3, 4, 9, 0, 0, 0), utc(2024, 3, 11, 9, 0, 0, 0)},
+		{"P1M", utc(2024, 3, 5, 8, 0, 0, 0), utc(2024, 3, 1, 9, 0, 0, 0), utc(2024, 4, 1, 9, 0, 0, 0)},
+		{"P1M", utc(2023, 12, 31, 0, 0, 0, 0), utc(2023, 12, 1, 9, 0, 0, 0), epoch},
+		{"P1Y", utc(2024, 1, 1, 9, 0, 0, 0), epoch, utc(2025, 1, 1, 9, 0, 0, 0)},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			p := MustParse(c.period)
+			g.Expect(p.PrevAlignment(c.now, epoch)).To(Equal(c.prev), info(i, c.period))
+			g.Expect(p.NextAlignment(c.now, epoch)).To(Equal(c.next), info(i, c.period))
+		})
+	}
+
+	// across the start of daylight saving, the local clock time is kept
+	daily := MustParse("P1D")
+	localEpoch := bst(2024, 1, 1, 9, 0, 0, 0)
+	g.Expect(daily.PrevAlignment(bst(2024, 3, 31, 12, 0, 0, 0), localEpoch)).To(Equal(bst(2024, 3, 31, 9, 0, 0, 0)))
+	g.Expect(daily.NextAlignment(bst(2024, 3, 31, 12, 0, 0, 0), localEpoch)).To(Equal(bst(2024, 4, 1, 9, 0, 0, 0)))
+
+	g.Expect(func() { Zero.PrevAlignment(epoch, epoch) }).To(Panic())
+
+	// the span is too long for time.Duration; this must panic, not hang
+	hourly := MustParse("PT1H")
+	g.Expect(func() { hourly.PrevAlignment(utc(2000, 1, 1, 0, 0, 0, 0), utc(1700, 1, 1, 0, 0, 0, 0)) }).To(Panic())
+	g.Expect(func() { hourly.NextAlignment(utc(2000, 1, 1, 0, 0, 0, 0), utc(1700, 1, 1, 0, 0, 0, 0)) }).To(Panic())
+}
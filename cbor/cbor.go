@@ -0,0 +1,37 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cbor provides CBOR (RFC 8949) encoding and decoding of periods, which is
+// useful for embedded systems and IoT protocols.
+//
+// A period is encoded as a CBOR text string holding its ISO-8601 representation,
+// e.g. "P1DT2H". This is compact, unambiguous and readable by any CBOR decoder.
+package cbor
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/rickb777/period"
+)
+
+// Encode converts a period to CBOR. The text is as given by MarshalText, so any decimal
+// fraction is written with '.' regardless of period.DecimalPoint.
+func Encode(p period.Period) ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(string(text))
+}
+
+// Decode converts CBOR data to a period. The data must hold a text string
+// containing an ISO-8601 period.
+func Decode(data []byte) (period.Period, error) {
+	var s string
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return period.Zero, fmt.Errorf("cannot decode CBOR as a period: %w", err)
+	}
+	return period.Parse(s)
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cbor
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/period"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value string
+		want  []byte
+	}{
+		{"P0D", []byte{0x63, 'P', '0', 'D'}},
+		{"P1D", []byte{0x63, 'P', '1', 'D'}},
+		{"-PT1.5S", []byte{0x67, '-', 'P', 'T', '1', '.', '5', 'S'}},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := period.MustParse(c.value)
+			bb, err := Encode(p)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(bb).To(Equal(c.want))
+
+			q, err := Decode(bb)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(q).To(Equal(p))
+		})
+	}
+}
+
+func TestEncodeIgnoresDecimalPoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func() { period.DecimalPoint = '.' }()
+	period.DecimalPoint = ','
+
+	bb, err := Encode(period.MustParse("PT1.5S"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bb).To(Equal([]byte{0x66, 'P', 'T', '1', '.', '5', 'S'}))
+}
+
+func TestDecodeErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := Decode([]byte{0x01}) // integer 1
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = Decode([]byte{0x63, 'X', 'Y', 'Z'})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("XYZ: expected 'P' period mark at the start"))
+}
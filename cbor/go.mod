@@ -0,0 +1,21 @@
+module github.com/rickb777/period/cbor
+
+go 1.22
+
+toolchain go1.23.2
+
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/onsi/gomega v1.36.2
+	github.com/rickb777/period v1.0.9
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/govalues/decimal v0.1.36 // indirect
+	github.com/rickb777/plural v1.4.2 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -23,6 +23,12 @@ const (
 	Year
 )
 
+// FieldOrder returns the seven designators in ISO-8601 canonical order, i.e. from years
+// down to seconds. A new slice is returned on each call, so it may be modified freely.
+func FieldOrder() []Designator {
+	return []Designator{Year, Month, Week, Day, Hour, Minute, Second}
+}
+
 func asDesignator(d byte, isHMS bool) (Designator, error) {
 	switch d {
 	case 'S':
@@ -44,6 +50,27 @@ func asDesignator(d byte, isHMS bool) (Designator, error) {
 	return 0, fmt.Errorf("expected a designator Y, M, W, D, H, or S not '%c'", d)
 }
 
+// String returns the name of the field, e.g. "years".
+func (d Designator) String() string {
+	switch d {
+	case Second:
+		return "seconds"
+	case Minute:
+		return "minutes"
+	case Hour:
+		return "hours"
+	case Day:
+		return "days"
+	case Week:
+		return "weeks"
+	case Month:
+		return "months"
+	case Year:
+		return "years"
+	}
+	return "Designator(" + strconv.Itoa(int(d)) + ")"
+}
+
 func (d Designator) Byte() byte {
 	switch d {
 	case Second:
@@ -64,26 +91,6 @@ func (d Designator) Byte() byte {
 	panic(strconv.Itoa(int(d)))
 }
 
-//func (d designator) field() string {
-//	switch d {
-//	case second:
-//		return "seconds"
-//	case minute:
-//		return "minutes"
-//	case hour:
-//		return "hours"
-//	case Day:
-//		return "days"
-//	case week:
-//		return "weeks"
-//	case month:
-//		return "months"
-//	case year:
-//		return "years"
-//	}
-//	panic(strconv.Itoa(int(d)))
-//}
-//
 //func (d designator) min(other designator) designator {
 //	if d < other {
 //		return d
@@ -0,0 +1,34 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFieldOrder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	order := FieldOrder()
+	g.Expect(order).To(Equal([]Designator{Year, Month, Week, Day, Hour, Minute, Second}))
+
+	p := MustParse("P1Y2M3W4DT5H6M7S")
+	for i, d := range order {
+		g.Expect(p.GetInt(d)).To(Equal(i+1), info(i, d))
+	}
+
+	order[0] = Second
+	g.Expect(FieldOrder()[0]).To(Equal(Year))
+}
+
+func TestDesignatorString(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Year.String()).To(Equal("years"))
+	g.Expect(Minute.String()).To(Equal("minutes"))
+	g.Expect(Designator(0).String()).To(Equal("Designator(0)"))
+}
@@ -5,31 +5,94 @@
 package period
 
 import (
+	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/govalues/decimal"
 	"github.com/rickb777/plural"
 )
 
-// Period converts the period to ISO-8601 string form.
-// If there is a decimal fraction, it will be rendered using a decimal point separator
-// (not a comma).
+// DecimalPoint is the separator written before any decimal fraction by String, WriteTo and the
+// methods based on them. ISO-8601 permits either '.' or ','; the default is '.'. Because Parse
+// accepts both by default (see DefaultParseOptions), text written with either remains parsable.
+// It is only used for display: MarshalText, and therefore JSON and gob, as well as the SQL Value
+// and Firestore encodings, always use '.' so that stored data does not depend on this setting.
+//
+// This should normally only be altered during program initialisation.
+var DecimalPoint byte = '.'
+
+// Period converts the period to ISO-8601 string form, typed as an ISOString.
+// If there is a decimal fraction, it will be rendered using the DecimalPoint separator.
+// See also ISODuration, which returns a plain string.
+//
+// Deprecated: use ISODuration or String.
 func (period Period) Period() ISOString {
 	return ISOString(period.String())
 }
 
-// ISOString converts the period to ISO-8601 string form.
-// If there is a decimal fraction, it will be rendered using a decimal point separator.
-// (not a comma).
+// String converts the period to ISO-8601 string form.
+// If there is a decimal fraction, it will be rendered using the DecimalPoint separator,
+// which is '.' unless altered.
+//
+// When built with the "periodpool" build tag, the working buffers are recycled via a sync.Pool,
+// which reduces allocations in high-throughput formatting.
 func (period Period) String() string {
-	buf := &strings.Builder{}
-	_, _ = period.WriteTo(buf)
-	return buf.String()
+	return formatISO(period, DecimalPoint)
+}
+
+// ISODuration converts the period to ISO-8601 string form. It is identical to String but
+// its name makes the intent clear at the call site. Period also gives the same content,
+// typed as an ISOString.
+func (period Period) ISODuration() string {
+	return period.String()
+}
+
+// FormatISOWeek converts the period to the "W<n>" form preferred by the ISO week calendar, e.g.
+// "W5" for five weeks. Any equivalent number of days is converted, so "P35D" also gives "W5".
+// Negative periods have a leading minus sign, e.g. "-W2".
+//
+// An error is returned if the period has any field other than weeks and days, or if it is not
+// a whole multiple of seven days.
+func (period Period) FormatISOWeek() (string, error) {
+	if period.years.Coef() != 0 || period.months.Coef() != 0 ||
+		period.hours.Coef() != 0 || period.minutes.Coef() != 0 || period.seconds.Coef() != 0 {
+		return "", fmt.Errorf("%s cannot be expressed in weeks: it has fields other than weeks and days", period)
+	}
+
+	weeks, rem, err := period.DaysIncWeeksDecimal().QuoRem(seven)
+	if err != nil || !rem.IsZero() || !weeks.IsInt() {
+		return "", fmt.Errorf("%s is not a whole number of weeks", period)
+	}
+
+	if weeks.Sign() < 0 {
+		return "-W" + weeks.Neg().Trunc(0).String(), nil
+	}
+	return "W" + weeks.Trunc(0).String(), nil
 }
 
-// WriteTo converts the period to ISO-8601 form.
+// FormatRoundTrip converts the period to ISO-8601 string form that is guaranteed to be parsed
+// back to an equal period by Parse, including for mixed-sign periods such as "P1Y-1M".
+// It is identical to String; the separate name documents this guarantee.
+//
+// In contrast, the human-readable output of Format cannot be parsed.
+func (period Period) FormatRoundTrip() string {
+	return period.String()
+}
+
+// WriteTo converts the period to ISO-8601 form and writes it to w, returning the number of
+// bytes written. It implements io.WriterTo, so a Period can be written directly to log
+// formatters, HTTP responses etc without first building a string.
+//
+// When built with the "periodpool" build tag, the text is assembled in a buffer recycled via a
+// sync.Pool and then passed to w in a single Write.
 func (period Period) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(period, w, DecimalPoint)
+}
+
+// writeISO implements WriteTo, using point as the separator before any decimal fraction.
+func (period Period) writeISO(w io.Writer, point byte) (int64, error) {
 	aw := adapt(w)
 
 	if period == Zero {
@@ -43,34 +106,47 @@ func (period Period) WriteTo(w io.Writer) (int64, error) {
 
 	_ = aw.WriteByte('P')
 
-	writeField(aw, period.years, Year)
-	writeField(aw, period.months, Month)
-	writeField(aw, period.weeks, Week)
-	writeField(aw, period.days, Day)
+	writeField(aw, period.years, Year, point)
+	writeField(aw, period.months, Month, point)
+	writeField(aw, period.weeks, Week, point)
+	writeField(aw, period.days, Day, point)
 
 	if period.hours.Coef() != 0 || period.minutes.Coef() != 0 || period.seconds.Coef() != 0 {
 		_ = aw.WriteByte('T')
 
-		writeField(aw, period.hours, Hour)
-		writeField(aw, period.minutes, Minute)
-		writeField(aw, period.seconds, Second)
+		writeField(aw, period.hours, Hour, point)
+		writeField(aw, period.minutes, Minute, point)
+		writeField(aw, period.seconds, Second, point)
 	}
 
 	return uwSum(aw)
 }
 
-func writeField(w usefulWriter, field decimal.Decimal, fieldDesignator Designator) {
+func writeField(w usefulWriter, field decimal.Decimal, fieldDesignator Designator, point byte) {
 	if field.Coef() != 0 {
-		_, _ = w.WriteString(field.String())
+		s := field.String()
+		if point != '.' {
+			s = strings.Replace(s, ".", string(point), 1)
+		}
+		_, _ = w.WriteString(s)
 		_ = w.WriteByte(fieldDesignator.Byte())
 	}
 }
 
 //-------------------------------------------------------------------------------------------------
 
+// FormatZero is the text that Format gives for the zero period. It can be set to the
+// preferred representation once, e.g. during program initialisation.
+var FormatZero = "zero"
+
 // Format converts the period to human-readable form using DefaultFormatLocalisation.
 // To adjust the result, see the Normalise, NormaliseDaysToYears, Simplify and SimplifyWeeksToDays methods.
+//
+// The zero period is formatted as FormatZero, which is "zero" unless altered.
 func (period Period) Format() string {
+	if period.IsZero() {
+		return FormatZero
+	}
 	return period.FormatLocalised(DefaultFormatLocalisation)
 }
 
@@ -81,6 +157,49 @@ func (period Period) FormatLocalised(config FormatLocalisation) string {
 		return config.ZeroValue
 	}
 
+	return strings.Join(period.formatParts(config), ", ")
+}
+
+// HumanDuration converts the period to human-readable English using DefaultFormatLocalisation,
+// joining the last two parts with "and". When there are three or more parts, the others are
+// separated by commas, including before the "and" (the Oxford comma). For example, "PT2H30M"
+// becomes "2 hours and 30 minutes" and "P1Y2M3D" becomes "1 year, 2 months, and 3 days".
+func (period Period) HumanDuration() string {
+	if period.IsZero() {
+		return DefaultFormatLocalisation.ZeroValue
+	}
+
+	parts := period.formatParts(DefaultFormatLocalisation)
+
+	switch len(parts) {
+	case 1:
+		return parts[0]
+	case 2:
+		return parts[0] + " and " + parts[1]
+	}
+
+	last := len(parts) - 1
+	return strings.Join(parts[:last], ", ") + ", and " + parts[last]
+}
+
+// ToRelativeTime describes the period in English relative to a reference time t, which is
+// typically time.Now(). A period that ends after t gives "in 3 days" and a period that ends
+// before t gives "2 hours ago"; the period itself is formatted as per HumanDuration. Using t to
+// decide the direction means that periods with mixed signs, such as "P1M-30D", are handled
+// correctly. If the period ends at t, the result is "now".
+func (period Period) ToRelativeTime(t time.Time) string {
+	end, _ := period.AddTo(t)
+
+	switch {
+	case end.After(t):
+		return "in " + period.HumanDuration()
+	case end.Before(t):
+		return period.HumanDuration() + " ago"
+	}
+	return "now"
+}
+
+func (period Period) formatParts(config FormatLocalisation) []string {
 	parts := make([]string, 0, 7)
 
 	parts = appendNonBlank(parts, formatField(period.years, config.Negate, config.YearNames))
@@ -91,7 +210,157 @@ func (period Period) FormatLocalised(config FormatLocalisation) string {
 	parts = appendNonBlank(parts, formatField(period.minutes, config.Negate, config.MinuteNames))
 	parts = appendNonBlank(parts, formatField(period.seconds, config.Negate, config.SecondNames))
 
-	return strings.Join(parts, ", ")
+	return parts
+}
+
+// FormatOptions controls FormatWithOptions.
+type FormatOptions struct {
+	// Locale selects the language, e.g. "en". Blank means "en". Only the locales that have been
+	// registered using RegisterFormatLocale are supported; others fall back to "en".
+	Locale string
+
+	// UseLongForm gives field names in full, e.g. "2 hours"; otherwise they are abbreviated, e.g. "2h".
+	UseLongForm bool
+
+	// Separator is placed between the fields. Blank means ", ".
+	Separator string
+
+	// NegativePrefix is placed before each negative field. Blank means the locale's default,
+	// which is "minus " for "en".
+	NegativePrefix string
+
+	// ZeroText is the result for the zero period. Blank means the locale's default, which is
+	// "zero" for "en".
+	ZeroText string
+}
+
+// formatLocale holds the long and abbreviated forms registered for one locale.
+type formatLocale struct {
+	long, short FormatLocalisation
+}
+
+// formatLocales holds the localisations used by FormatWithOptions, keyed by locale.
+var formatLocales = map[string]formatLocale{
+	"en": {
+		long: DefaultFormatLocalisation,
+		short: FormatLocalisation{
+			ZeroValue:   "zero",
+			Negate:      func(s string) string { return "minus " + s },
+			YearNames:   plural.FromZero("", "%vy", "%vy"),
+			MonthNames:  plural.FromZero("", "%vmo", "%vmo"),
+			WeekNames:   plural.FromZero("", "%vw", "%vw"),
+			DayNames:    plural.FromZero("", "%vd", "%vd"),
+			HourNames:   plural.FromZero("", "%vh", "%vh"),
+			MinuteNames: plural.FromZero("", "%vmin", "%vmin"),
+			SecondNames: plural.FromZero("", "%vs", "%vs"),
+		},
+	},
+}
+
+// RegisterFormatLocale adds support for a language to FormatWithOptions. The long localisation
+// is used when FormatOptions.UseLongForm is set and the short one otherwise; their Negate
+// functions provide the default when FormatOptions.NegativePrefix is blank. Registering an
+// existing locale, including "en", replaces it.
+//
+// This should normally only be called during program initialisation.
+func RegisterFormatLocale(locale string, long, short FormatLocalisation) {
+	formatLocales[locale] = formatLocale{long: long, short: short}
+}
+
+// FormatWithOptions converts the period to human-readable form as controlled by opts. Unlike
+// Format, the overall sign of the period is shown: every negative field is preceded by the
+// NegativePrefix. For example, "-PT2H30M" becomes "minus 2 hours, minus 30 minutes" using the
+// long form, or "minus 2h, minus 30min" otherwise.
+//
+// Only the "en" locale is provided; see RegisterFormatLocale for others.
+func (period Period) FormatWithOptions(opts FormatOptions) string {
+	locale, exists := formatLocales[opts.Locale]
+	if !exists {
+		locale = formatLocales["en"]
+	}
+
+	config := locale.short
+	if opts.UseLongForm {
+		config = locale.long
+	}
+
+	if period.IsZero() {
+		if opts.ZeroText != "" {
+			return opts.ZeroText
+		}
+		return config.ZeroValue
+	}
+
+	separator := opts.Separator
+	if separator == "" {
+		separator = ", "
+	}
+
+	negate := config.Negate
+	if opts.NegativePrefix != "" {
+		negate = func(s string) string { return opts.NegativePrefix + s }
+	}
+
+	parts := make([]string, 0, 7)
+	for _, d := range FieldOrder() {
+		parts = appendNonBlank(parts, formatField(period.GetField(d), negate, config.names(d)))
+	}
+
+	return strings.Join(parts, separator)
+}
+
+// FormatDays converts the period to a human-readable number of days using DefaultFormatLocalisation,
+// e.g. "3 days". This is intended for summaries such as date-range displays.
+//
+// A flag is also returned that is true when the count is precise, and false otherwise. Weeks
+// and days are counted precisely. Years and months are approximated (assuming a year is 365.2425
+// days and a month is 1/12 of that), as are hours, minutes and seconds (assuming all days are
+// 24 hours long); approximate counts are rounded to two decimal places.
+func (period Period) FormatDays() (string, bool) {
+	return period.FormatDaysLocalised(DefaultFormatLocalisation)
+}
+
+// FormatDaysLocalised converts the period to a human-readable number of days in a localisable way.
+// See FormatDays.
+func (period Period) FormatDaysLocalised(config FormatLocalisation) (string, bool) {
+	if period.IsZero() {
+		return config.ZeroValue, true
+	}
+
+	days, precise := period.Abs().totalDays()
+	if days.IsZero() {
+		return config.ZeroValue, precise
+	}
+	return formatField(days, config.Negate, config.DayNames), precise
+}
+
+func (period Period) totalDays() (decimal.Decimal, bool) {
+	days := period.DaysIncWeeksDecimal()
+	precise := true
+
+	if period.years.Coef() != 0 || period.months.Coef() != 0 {
+		months, _ := period.YearsDecimal().Mul(twelve)
+		months, _ = months.Add(period.MonthsDecimal())
+		ym, _ := months.Mul(daysPerYear)
+		ym, _ = ym.Quo(twelve)
+		days, _ = days.Add(ym)
+		precise = false
+	}
+
+	if period.hours.Coef() != 0 || period.minutes.Coef() != 0 || period.seconds.Coef() != 0 {
+		hms, _ := totalHrMinSec(period)
+		if period.neg {
+			hms = -hms
+		}
+		hd, _ := decimal.MustNew(int64(hms), 9).Quo(secondsPerDayDecimal)
+		days, _ = days.Add(hd)
+		precise = false
+	}
+
+	if !precise {
+		days = days.Round(2)
+	}
+	return days.Trim(0), precise
 }
 
 func formatField(field decimal.Decimal, negate func(string) string, names plural.Plurals) string {
@@ -123,6 +392,25 @@ type FormatLocalisation struct {
 	HourNames, MinuteNames, SecondNames        plural.Plurals
 }
 
+// names gives the plurals for the field d.
+func (config FormatLocalisation) names(d Designator) plural.Plurals {
+	switch d {
+	case Year:
+		return config.YearNames
+	case Month:
+		return config.MonthNames
+	case Week:
+		return config.WeekNames
+	case Day:
+		return config.DayNames
+	case Hour:
+		return config.HourNames
+	case Minute:
+		return config.MinuteNames
+	}
+	return config.SecondNames
+}
+
 // DefaultFormatLocalisation provides the formatting strings needed to format Period values in vernacular English.
 var DefaultFormatLocalisation = FormatLocalisation{
 	ZeroValue: "zero",
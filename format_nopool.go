@@ -0,0 +1,22 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !periodpool
+
+package period
+
+import (
+	"io"
+	"strings"
+)
+
+func formatISO(period Period, point byte) string {
+	buf := &strings.Builder{}
+	_, _ = period.writeISO(buf, point)
+	return buf.String()
+}
+
+func writeTo(period Period, w io.Writer, point byte) (int64, error) {
+	return period.writeISO(w, point)
+}
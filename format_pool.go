@@ -0,0 +1,36 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build periodpool
+
+package period
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// bufferPool holds bytes.Buffer rather than strings.Builder because a builder cannot be reused
+// after its String method has been called without losing its storage.
+var bufferPool = sync.Pool{New: func() any { return &bytes.Buffer{} }}
+
+func formatISO(period Period, point byte) string {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	_, _ = period.writeISO(buf, point)
+	s := buf.String()
+	bufferPool.Put(buf)
+	return s
+}
+
+// writeTo assembles the text in a pooled buffer, so that w receives a single Write.
+func writeTo(period Period, w io.Writer, point byte) (int64, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	_, _ = period.writeISO(buf, point)
+	n, err := w.Write(buf.Bytes())
+	bufferPool.Put(buf)
+	return int64(n), err
+}
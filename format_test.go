@@ -1,9 +1,14 @@
 package period
 
 import (
+	"bytes"
 	"fmt"
 	. "github.com/onsi/gomega"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/rickb777/plural"
 )
 
 func Test_String(t *testing.T) {
@@ -70,6 +75,7 @@ func Test_String(t *testing.T) {
 			// check the normal case
 			sp1 := c.p64.Period()
 			g.Expect(sp1).To(Equal(c.expected))
+			g.Expect(c.p64.ISODuration()).To(Equal(string(c.expected)))
 
 			// check the negative case
 			if !c.p64.IsZero() {
@@ -91,6 +97,90 @@ func Test_String(t *testing.T) {
 	}
 }
 
+func Test_WriteTo_counts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := MustParse("-P1Y2M3W4DT5H6M7.8S")
+
+	buf := &bytes.Buffer{}
+	n, err := p.WriteTo(buf)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(Equal("-P1Y2M3W4DT5H6M7.8S"))
+	g.Expect(n).To(Equal(int64(buf.Len())))
+
+	sb := &strings.Builder{}
+	n, err = Zero.WriteTo(sb)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sb.String()).To(Equal("P0D"))
+	g.Expect(n).To(Equal(int64(3)))
+}
+
+func Test_String_DecimalPoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func() { DecimalPoint = '.' }()
+
+	p := MustParse("P2YT1.25S")
+	DecimalPoint = ','
+	g.Expect(p.String()).To(Equal("P2YT1,25S"))
+
+	buf := &bytes.Buffer{}
+	_, err := p.WriteTo(buf)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(Equal("P2YT1,25S"))
+
+	g.Expect(MustParse(p.String())).To(Equal(p))
+
+	// the encodings are not affected
+	text, err := p.MarshalText()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(text)).To(Equal("P2YT1.25S"))
+
+	j, err := p.ToJSON()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(j)).To(Equal(`"P2YT1.25S"`))
+
+	v, err := p.Value()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v).To(Equal("P2YT1.25S"))
+
+	gob, err := p.GobEncode()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(gob[1:])).To(Equal("P2YT1.25S"))
+
+	fs, err := p.MarshalFirestore()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fs).To(Equal(map[string]interface{}{"ISO": "P2YT1.25S"}))
+}
+
+func Test_FormatISOWeek(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		expected string
+	}{
+		{"P0D", "W0"},
+		{"P5W", "W5"},
+		{"P35D", "W5"},
+		{"P1W14D", "W3"},
+		{"P2W-7D", "W1"},
+		{"-P2W", "-W2"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			s, err := MustParse(c.period).FormatISOWeek()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+
+	for i, bad := range []string{"P8D", "P1.5W", "P1M", "P1WT1H", "PT168H"} {
+		_, err := MustParse(bad).FormatISOWeek()
+		g.Expect(err).To(HaveOccurred(), info(i, bad))
+	}
+}
+
 // simpleBuffer intentionally only has Write method.
 type simpleBuffer struct {
 	bs []byte
@@ -164,3 +254,176 @@ func Test_Format(t *testing.T) {
 		})
 	}
 }
+
+func Test_Format_mixed_signs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		expected string
+	}{
+		// note: the negative cases are also covered (see below)
+
+		{"P1Y-1M1W-1DT1H-1M1S", "1 year, minus 1 month, 1 week, minus 1 day, 1 hour, minus 1 minute, 1 second"},
+		{"P1Y-1M-1W-1DT-1H-1M-1S", "1 year, minus 1 month, minus 1 week, minus 1 day, minus 1 hour, minus 1 minute, minus 1 second"},
+		{"P-1Y1M1W1DT1H1M1S", "1 year, minus 1 month, minus 1 week, minus 1 day, minus 1 hour, minus 1 minute, minus 1 second"},
+		{"P2M-3W4DT-5H6M-7S", "2 months, minus 3 weeks, 4 days, minus 5 hours, 6 minutes, minus 7 seconds"},
+		{"P1W-1D", "1 week, minus 1 day"},
+		{"P-1Y-1M1W", "1 year, 1 month, minus 1 week"},
+		{"PT1H-1.5M", "1 hour, minus 1.5 minutes"},
+		{"PT-1M1S", "1 minute, minus 1 second"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			p := MustParse(c.period)
+			sp := p.Format()
+			g.Expect(sp).To(Equal(c.expected), info(i, "%s -> %s", p, c.expected))
+
+			en := p.Negate()
+			sn := en.Format()
+			g.Expect(sn).To(Equal(c.expected), info(i, "%s -> %s", en, c.expected))
+		})
+	}
+}
+
+func Test_HumanDuration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		expected string
+	}{
+		{"P0D", "zero"},
+		{"PT1H", "1 hour"},
+		{"PT2H30M", "2 hours and 30 minutes"},
+		{"P1Y2M3D", "1 year, 2 months, and 3 days"},
+		{"P1Y2M3W4DT5H6M7S", "1 year, 2 months, 3 weeks, 4 days, 5 hours, 6 minutes, and 7 seconds"},
+		{"P1W-1D", "1 week and minus 1 day"},
+		{"-P1DT12H", "1 day and 12 hours"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			s := MustParse(c.period).HumanDuration()
+			g.Expect(s).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+}
+
+func Test_ToRelativeTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		t0       time.Time
+		expected string
+	}{
+		{"P0D", utc(2024, 1, 1, 0, 0, 0, 0), "now"},
+		{"P3D", utc(2024, 1, 1, 0, 0, 0, 0), "in 3 days"},
+		{"-PT2H", utc(2024, 1, 1, 0, 0, 0, 0), "2 hours ago"},
+		{"-P1Y2M3D", utc(2024, 1, 1, 0, 0, 0, 0), "1 year, 2 months, and 3 days ago"},
+		{"P1M-30D", utc(2024, 1, 1, 0, 0, 0, 0), "in 1 month and minus 30 days"},  // January has 31 days
+		{"P1M-30D", utc(2024, 2, 1, 0, 0, 0, 0), "1 month and minus 30 days ago"}, // February has 29 days
+		{"P1M-29D", utc(2024, 2, 1, 0, 0, 0, 0), "now"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			s := MustParse(c.period).ToRelativeTime(c.t0)
+			g.Expect(s).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+}
+
+func Test_FormatDays(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		expected string
+		precise  bool
+	}{
+		// note: the negative cases are also covered (see below)
+
+		{"P0D", "zero", true},
+		{"P1D", "1 day", true},
+		{"P3D", "3 days", true},
+		{"P1W", "7 days", true},
+		{"P2W3D", "17 days", true},
+		{"P1.5D", "1.5 days", true},
+		{"P1W-1D", "6 days", true},
+		{"PT36H", "1.5 days", false},
+		{"P1DT12H", "1.5 days", false},
+		{"PT1S", "zero", false}, // rounded
+		{"PT60H", "2.5 days", false},
+		{"P1M", "30.44 days", false},
+		{"P1Y", "365.24 days", false},
+		{"P1DT-72H", "minus 2 days", false},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			p := MustParse(c.period)
+			sp, precise := p.FormatDays()
+			g.Expect(sp).To(Equal(c.expected), info(i, "%s -> %s", p, c.expected))
+			g.Expect(precise).To(Equal(c.precise), info(i, "%s -> %s", p, c.expected))
+
+			sn, precise := p.Negate().FormatDays()
+			g.Expect(sn).To(Equal(c.expected), info(i, "%s -> %s", p, c.expected))
+			g.Expect(precise).To(Equal(c.precise), info(i, "%s -> %s", p, c.expected))
+		})
+	}
+}
+
+func Test_Format_custom_zero(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Zero.Format()).To(Equal("zero"))
+
+	defer func() { FormatZero = "zero" }()
+
+	FormatZero = "nothing"
+	g.Expect(Zero.Format()).To(Equal("nothing"))
+	g.Expect(MustParse("P1D").Format()).To(Equal("1 day"))
+
+	fr := DefaultFormatLocalisation
+	fr.ZeroValue = "zéro"
+	g.Expect(Zero.FormatLocalised(fr)).To(Equal("zéro"))
+}
+
+func Test_FormatWithOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	long := FormatOptions{UseLongForm: true}
+	cases := []struct {
+		period   string
+		opts     FormatOptions
+		expected string
+	}{
+		{"P0D", long, "zero"},
+		{"P1Y2M3W4DT5H6M7S", long, "1 year, 2 months, 3 weeks, 4 days, 5 hours, 6 minutes, 7 seconds"},
+		{"P1Y2M3W4DT5H6M7S", FormatOptions{}, "1y, 2mo, 3w, 4d, 5h, 6min, 7s"},
+		{"PT2H30M", FormatOptions{Separator: " "}, "2h 30min"},
+		{"PT1.5S", long, "1.5 seconds"},
+		{"-PT2H30M", long, "minus 2 hours, minus 30 minutes"},
+		{"-PT2H30M", FormatOptions{NegativePrefix: "-", Separator: " "}, "-2h -30min"},
+		{"P1W-1D", long, "1 week, minus 1 day"},
+		{"P1D", FormatOptions{Locale: "xx", UseLongForm: true}, "1 day"},
+		{"P0D", FormatOptions{ZeroText: "none"}, "none"},
+		{"P1D", FormatOptions{ZeroText: "none"}, "1d"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.period), func(t *testing.T) {
+			s := MustParse(c.period).FormatWithOptions(c.opts)
+			g.Expect(s).To(Equal(c.expected), info(i, c.period))
+		})
+	}
+
+	defer delete(formatLocales, "fr")
+
+	fr := DefaultFormatLocalisation
+	fr.ZeroValue = "zéro"
+	fr.Negate = func(s string) string { return "moins " + s }
+	fr.DayNames = plural.FromZero("", "%v jour", "%v jours")
+	RegisterFormatLocale("fr", fr, fr)
+
+	g.Expect(Zero.FormatWithOptions(FormatOptions{Locale: "fr"})).To(Equal("zéro"))
+	g.Expect(MustParse("-P2D").FormatWithOptions(FormatOptions{Locale: "fr"})).To(Equal("moins 2 jours"))
+}
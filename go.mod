@@ -8,6 +8,7 @@ require (
 	github.com/govalues/decimal v0.1.32
 	github.com/onsi/gomega v1.35.0
 	github.com/rickb777/plural v1.4.2
+	pgregory.net/rapid v1.2.0
 )
 
 require (
@@ -0,0 +1,283 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/govalues/decimal"
+)
+
+// ToJavaPeriod converts the years, months, weeks and days of the period to the string form used by
+// Java's java.time.Period, which has whole-number years, months and days. Weeks are converted to
+// days. Hours, minutes and seconds are disregarded; see ToJavaDuration for those.
+//
+// The result matches java.time.Period.toString, in which each field carries its own sign, e.g.
+// "-P1Y2M" gives "P-1Y-2M". The zero period gives "P0D".
+//
+// An error arises if any of the fields has a fraction, or if a field exceeds the range of a Java int.
+func (period Period) ToJavaPeriod() (string, error) {
+	days, err := addMultiple(period.GetField(Day), period.GetField(Week), seven)
+	if err != nil {
+		return "", err
+	}
+
+	fields := []decimal.Decimal{period.GetField(Year), period.GetField(Month), days}
+	designators := []byte{'Y', 'M', 'D'}
+
+	buf := &strings.Builder{}
+	buf.WriteByte('P')
+	for i, f := range fields {
+		if !f.IsInt() {
+			return "", fmt.Errorf("%s cannot be expressed as a java.time.Period: it has a fraction", period)
+		}
+
+		n, _, ok := f.Int64(0)
+		if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+			return "", fmt.Errorf("%s cannot be expressed as a java.time.Period: it is too large", period)
+		}
+
+		if n != 0 {
+			buf.WriteString(strconv.FormatInt(n, 10))
+			buf.WriteByte(designators[i])
+		}
+	}
+
+	if buf.Len() == 1 {
+		return "P0D", nil
+	}
+	return buf.String(), nil
+}
+
+// ToJavaDuration converts the hours, minutes and seconds of the period to the string form used by
+// Java's java.time.Duration. Years, months, weeks and days are disregarded; see ToJavaPeriod for
+// those.
+//
+// The result matches java.time.Duration.toString, so it is normalised to hours, minutes and
+// seconds and each field carries its own sign, e.g. "PT90M" gives "PT1H30M" and "-PT1H30M" gives
+// "PT-1H-30M". The zero period gives "PT0S".
+//
+// An error arises if the hours, minutes and seconds do not amount to a whole number of seconds,
+// or if the total is too large.
+func (period Period) ToJavaDuration() (string, error) {
+	t, err := period.OnlyHMS().totals()
+	if err != nil {
+		return "", err
+	}
+
+	if !t.seconds.IsInt() {
+		return "", fmt.Errorf("%s cannot be expressed as a java.time.Duration: it is not a whole number of seconds", period)
+	}
+
+	n, _, ok := t.seconds.Int64(0)
+	if !ok {
+		return "", fmt.Errorf("%s cannot be expressed as a java.time.Duration: it is too large", period)
+	}
+
+	if n == 0 {
+		return "PT0S", nil
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString("PT")
+	writeJavaField(buf, n/3600, 'H')
+	writeJavaField(buf, (n%3600)/60, 'M')
+	writeJavaField(buf, n%60, 'S')
+	return buf.String(), nil
+}
+
+func writeJavaField(buf *strings.Builder, n int64, designator byte) {
+	if n != 0 {
+		buf.WriteString(strconv.FormatInt(n, 10))
+		buf.WriteByte(designator)
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// ToApacheSpark converts the period to an Apache Spark SQL interval literal, e.g. "P2Y3M" gives
+// "INTERVAL '2 years 3 months'". Like Spark's own output, the unit names are always plural and each
+// field carries its own sign, so "-P1DT2H" gives "INTERVAL '-1 days -2 hours'". Only the non-zero
+// fields are included; the zero period gives "INTERVAL '0 seconds'".
+//
+// See ParseApacheSpark for the reverse conversion.
+func (period Period) ToApacheSpark() string {
+	parts := make([]string, 0, 7)
+	for _, d := range FieldOrder() {
+		v := period.GetField(d)
+		if !v.IsZero() {
+			parts = append(parts, v.String()+" "+d.String())
+		}
+	}
+
+	if len(parts) == 0 {
+		return "INTERVAL '0 seconds'"
+	}
+	return "INTERVAL '" + strings.Join(parts, " ") + "'"
+}
+
+// sparkUnits maps the Apache Spark interval units to period fields. The units that are smaller
+// than a second also have the number of them per second.
+var sparkUnits = map[string]struct {
+	field     Designator
+	perSecond decimal.Decimal
+}{
+	"year":        {field: Year},
+	"month":       {field: Month},
+	"week":        {field: Week},
+	"day":         {field: Day},
+	"hour":        {field: Hour},
+	"minute":      {field: Minute},
+	"second":      {field: Second},
+	"millisecond": {field: Second, perSecond: millisecondsPerSecond},
+	"microsecond": {field: Second, perSecond: microsecondsPerSecond},
+}
+
+// ParseApacheSpark parses an Apache Spark SQL interval literal such as "INTERVAL '2 years 3 months'".
+// The "INTERVAL" keyword and the quotes are optional, so "2 years 3 months" is also accepted.
+// The units are case-insensitive and may be singular or plural; they are year, month, week, day,
+// hour, minute, second, millisecond and microsecond. Milliseconds and microseconds are converted
+// to seconds. A unit may be repeated, in which case its values are added together.
+//
+// As for NewDecimal, only the least significant non-zero field can have a fraction.
+func ParseApacheSpark(s string) (Period, error) {
+	text := strings.TrimSpace(s)
+	if len(text) >= 8 && strings.EqualFold(text[:8], "INTERVAL") {
+		text = strings.TrimSpace(text[8:])
+	}
+	if len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'' {
+		text = text[1 : len(text)-1]
+	}
+
+	words := strings.Fields(text)
+	if len(words) == 0 || len(words)%2 != 0 {
+		return Zero, fmt.Errorf("%s: expected pairs of numbers and units", s)
+	}
+
+	var fields [7]decimal.Decimal
+	for i := 0; i < len(words); i += 2 {
+		number, err := decimal.Parse(words[i])
+		if err != nil {
+			return Zero, fmt.Errorf("%s: %q is not a number", s, words[i])
+		}
+
+		unit, exists := sparkUnits[strings.TrimSuffix(strings.ToLower(words[i+1]), "s")]
+		if !exists {
+			return Zero, fmt.Errorf("%s: %q is not a known unit", s, words[i+1])
+		}
+
+		if !unit.perSecond.IsZero() {
+			number, err = number.Quo(unit.perSecond)
+			if err != nil {
+				return Zero, fmt.Errorf("%s: %w", s, err)
+			}
+		}
+
+		index := int(Year - unit.field)
+		fields[index], err = fields[index].Add(number)
+		if err != nil {
+			return Zero, fmt.Errorf("%s: %w", s, err)
+		}
+	}
+
+	return NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// elasticUnits holds the Elasticsearch date math units, in the same order as FieldOrder.
+const elasticUnits = "yMwdhms"
+
+// ToElasticDateMath converts the period to an Elasticsearch date math expression relative to
+// anchor, e.g. "P1M2D" gives "now+1M+2d". A blank anchor means "now"; an explicit date anchor
+// must include its "||" separator, e.g. "2001-02-01||". Each non-zero field carries its own sign,
+// so "-P1DT2H" gives "now-1d-2h"; the zero period gives just the anchor.
+//
+// An error arises if any field has a fraction, because date math only supports whole numbers.
+// See ParseElasticDateMath for the reverse conversion.
+func (period Period) ToElasticDateMath(anchor string) (string, error) {
+	if anchor == "" {
+		anchor = "now"
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString(anchor)
+	for i, d := range FieldOrder() {
+		v := period.GetField(d)
+		if !v.IsInt() {
+			return "", fmt.Errorf("%s cannot be expressed as Elasticsearch date math: it has a fraction", period)
+		}
+		if !v.IsZero() {
+			if v.Sign() > 0 {
+				buf.WriteByte('+')
+			}
+			buf.WriteString(v.Trunc(0).String())
+			buf.WriteByte(elasticUnits[i])
+		}
+	}
+	return buf.String(), nil
+}
+
+// ParseElasticDateMath parses an Elasticsearch date math expression such as "now+1M-2d" and
+// returns the period that it adds to its anchor. The anchor is either "now" or a date followed by
+// "||"; it is discarded. The units are y, M, w, d, h (or H), m and s. A unit may be repeated, in
+// which case its values are added together.
+//
+// Rounding, such as "/d", cannot be represented by a period, so it gives an error.
+func ParseElasticDateMath(s string) (Period, error) {
+	text := s
+	if strings.HasPrefix(text, "now") {
+		text = text[3:]
+	} else if i := strings.Index(text, "||"); i >= 0 {
+		text = text[i+2:]
+	} else {
+		return Zero, fmt.Errorf("%s: expected 'now' or a date followed by '||'", s)
+	}
+
+	var fields [7]decimal.Decimal
+	for len(text) > 0 {
+		sign := text[0]
+		if sign != '+' && sign != '-' {
+			if sign == '/' {
+				return Zero, fmt.Errorf("%s: rounding cannot be expressed as a period", s)
+			}
+			return Zero, fmt.Errorf("%s: expected '+' or '-' not '%c'", s, sign)
+		}
+
+		j := 1
+		for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+			j++
+		}
+		if j == 1 || j == len(text) {
+			return Zero, fmt.Errorf("%s: expected a number followed by a unit", s)
+		}
+
+		unit := text[j]
+		if unit == 'H' {
+			unit = 'h'
+		}
+		index := strings.IndexByte(elasticUnits, unit)
+		if index < 0 {
+			return Zero, fmt.Errorf("%s: expected a unit y, M, w, d, h, H, m or s not '%c'", s, text[j])
+		}
+
+		number, err := decimal.Parse(text[:j])
+		if err != nil {
+			return Zero, fmt.Errorf("%s: %w", s, err)
+		}
+
+		fields[index], err = fields[index].Add(number)
+		if err != nil {
+			return Zero, fmt.Errorf("%s: %w", s, err)
+		}
+
+		text = text[j+1:]
+	}
+
+	return NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+}
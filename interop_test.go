@@ -0,0 +1,181 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestToJavaPeriod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"P0D", "P0D"},
+		{"PT1H", "P0D"},
+		{"P1Y2M3D", "P1Y2M3D"},
+		{"P1Y2M3W4DT5H6M7S", "P1Y2M25D"},
+		{"-P1Y2M", "P-1Y-2M"},
+		{"P1M-1D", "P1M-1D"},
+		{"P2W", "P14D"},
+		{"P1.0Y", "P1Y"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			s, err := MustParse(c.value).ToJavaPeriod()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.expected), info(i, c.value))
+		})
+	}
+
+	_, err := MustParse("P1.5Y").ToJavaPeriod()
+	g.Expect(err).To(MatchError("P1.5Y cannot be expressed as a java.time.Period: it has a fraction"))
+
+	_, err = MustParse("P3000000000D").ToJavaPeriod()
+	g.Expect(err).To(MatchError("P3000000000D cannot be expressed as a java.time.Period: it is too large"))
+}
+
+func TestToJavaDuration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"P0D", "PT0S"},
+		{"P1D", "PT0S"},
+		{"PT1H", "PT1H"},
+		{"PT90M", "PT1H30M"},
+		{"PT1.5H", "PT1H30M"},
+		{"PT8H6M12S", "PT8H6M12S"},
+		{"-PT1H30M", "PT-1H-30M"},
+		{"PT1H-1S", "PT59M59S"},
+		{"P1DT100000S", "PT27H46M40S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			s, err := MustParse(c.value).ToJavaDuration()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.expected), info(i, c.value))
+		})
+	}
+
+	_, err := MustParse("PT1.5S").ToJavaDuration()
+	g.Expect(err).To(MatchError("PT1.5S cannot be expressed as a java.time.Duration: it is not a whole number of seconds"))
+}
+
+func TestApacheSpark(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"P0D", "INTERVAL '0 seconds'"},
+		{"P2Y3M", "INTERVAL '2 years 3 months'"},
+		{"P1Y2M3W4DT5H6M7.5S", "INTERVAL '1 years 2 months 3 weeks 4 days 5 hours 6 minutes 7.5 seconds'"},
+		{"-P1DT2H", "INTERVAL '-1 days -2 hours'"},
+		{"P1M-1D", "INTERVAL '1 months -1 days'"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := MustParse(c.value)
+			s := p.ToApacheSpark()
+			g.Expect(s).To(Equal(c.expected), info(i, c.value))
+
+			back, err := ParseApacheSpark(s)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(back).To(Equal(p), info(i, c.value))
+		})
+	}
+}
+
+func TestParseApacheSpark(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"interval '1 year 1 Day'", "P1Y1D"},
+		{"3 hours 30 minutes", "PT3H30M"},
+		{"'1 day 1 day'", "P2D"},
+		{"INTERVAL '1 second 500 milliseconds'", "PT1.5S"},
+		{"INTERVAL '250 microseconds'", "PT0.00025S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p, err := ParseApacheSpark(c.value)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.value))
+		})
+	}
+
+	for i, bad := range []string{"", "INTERVAL ''", "1 year 2", "x years", "1 fortnight", "1.5 years 1 day"} {
+		_, err := ParseApacheSpark(bad)
+		g.Expect(err).To(HaveOccurred(), info(i, bad))
+	}
+}
+
+func TestElasticDateMath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		anchor   string
+		expected string
+	}{
+		{"P0D", "", "now"},
+		{"P1D", "", "now+1d"},
+		{"-P1M", "now", "now-1M"},
+		{"P1Y2M3W4DT5H6M7S", "", "now+1y+2M+3w+4d+5h+6m+7s"},
+		{"P1M-2D", "2001-02-01||", "2001-02-01||+1M-2d"},
+		{"-P1DT2H", "", "now-1d-2h"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := MustParse(c.value)
+			s, err := p.ToElasticDateMath(c.anchor)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(c.expected), info(i, c.value))
+
+			back, err := ParseElasticDateMath(s)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(back).To(Equal(p), info(i, c.value))
+		})
+	}
+
+	_, err := MustParse("PT1.5H").ToElasticDateMath("")
+	g.Expect(err).To(MatchError("PT1.5H cannot be expressed as Elasticsearch date math: it has a fraction"))
+}
+
+func TestParseElasticDateMath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, err := ParseElasticDateMath("now+1H+1d+1d")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("P2DT1H")))
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"1d", "1d: expected 'now' or a date followed by '||'"},
+		{"now+1d/d", "now+1d/d: rounding cannot be expressed as a period"},
+		{"now1d", "now1d: expected '+' or '-' not '1'"},
+		{"now+d", "now+d: expected a number followed by a unit"},
+		{"now+1", "now+1: expected a number followed by a unit"},
+		{"now+1x", "now+1x: expected a unit y, M, w, d, h, H, m or s not 'x'"},
+	}
+	for i, c := range cases {
+		_, err := ParseElasticDateMath(c.value)
+		g.Expect(err).To(MatchError(c.expected), info(i, c.value))
+	}
+}
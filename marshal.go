@@ -4,23 +4,67 @@
 
 package period
 
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/govalues/decimal"
+)
+
+// gobVersion1 is the first byte of gob-encoded periods; the ISO-8601 text follows it.
+const gobVersion1 byte = 1
+
+// GobEncode implements the gob.GobEncoder interface. The encoding is a version byte
+// followed by the ISO-8601 string, which allows the format to evolve in future.
+func (period Period) GobEncode() ([]byte, error) {
+	text, err := period.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{gobVersion1}, text...), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface. Data without a version byte,
+// as written by earlier releases via MarshalBinary, is also accepted.
+func (period *Period) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		return period.UnmarshalText(data)
+	}
+
+	switch data[0] {
+	case gobVersion1:
+		return period.UnmarshalText(data[1:])
+	case 'P', '-', '+':
+		return period.UnmarshalText(data)
+	}
+
+	return fmt.Errorf("unsupported gob encoding version %d for a period", data[0])
+}
+
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
-// This also provides support for gob encoding.
 func (period Period) MarshalBinary() ([]byte, error) {
 	// binary method would take more space in many cases, so we simply use text
 	return period.MarshalText()
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-// This also provides support for gob decoding.
 func (period *Period) UnmarshalBinary(data []byte) error {
 	return period.UnmarshalText(data)
 }
 
 // MarshalText implements the encoding.TextMarshaler interface for Periods.
-// This also provides support for JSON encoding.
+// This also provides support for JSON encoding. Any decimal fraction is always written with
+// '.', regardless of DecimalPoint.
 func (period Period) MarshalText() ([]byte, error) {
-	return []byte(period.String()), nil
+	return []byte(formatISO(period, '.')), nil
+}
+
+// ToJSON converts the period to JSON, i.e. its ISO-8601 form as a quoted string such as "P1Y2M".
+// This is the same as json.Marshal(period); the method is provided for convenience.
+func (period Period) ToJSON() ([]byte, error) {
+	return json.Marshal(period)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface for Periods.
@@ -32,3 +76,164 @@ func (period *Period) UnmarshalText(data []byte) error {
 	}
 	return err
 }
+
+//-------------------------------------------------------------------------------------------------
+
+// negKey is the ToDecimalMap key that holds the sign.
+const negKey = "neg"
+
+// ToDecimalMap converts the period to a map, which is useful for serialisation to BSON, DynamoDB,
+// Redis hashes and the like. The keys are the field names as given by Designator.String, e.g.
+// "years" and "minutes"; only the non-zero fields are included. There is also a "neg" entry that
+// is 1 for negative periods and 0 otherwise. The field values exclude the overall sign, so "-P1M"
+// gives months 1 and neg 1, whereas "P1M-1D" gives days -1 and neg 0.
+//
+// See NewFromDecimalMap for the reverse conversion.
+func (period Period) ToDecimalMap() map[string]decimal.Decimal {
+	m := make(map[string]decimal.Decimal, 8)
+
+	abs := period.Abs()
+	for _, d := range FieldOrder() {
+		v := abs.GetField(d)
+		if !v.IsZero() {
+			m[d.String()] = v
+		}
+	}
+
+	if period.neg {
+		m[negKey] = decimal.One
+	} else {
+		m[negKey] = decimal.Zero
+	}
+
+	return m
+}
+
+// NewFromDecimalMap converts a map created by ToDecimalMap back to a period. Missing fields are
+// treated as zero, as is a missing "neg" entry. An error arises if there are unknown keys, if "neg"
+// is not 0 or 1, or if more than one field has a fraction (see NewDecimal).
+func NewFromDecimalMap(m map[string]decimal.Decimal) (Period, error) {
+	var fields [7]decimal.Decimal
+	found := 0
+
+	for i, d := range FieldOrder() {
+		if v, exists := m[d.String()]; exists {
+			fields[i] = v
+			found++
+		}
+	}
+
+	neg, exists := m[negKey]
+	if exists {
+		found++
+		if !neg.IsZero() && neg.Cmp(decimal.One) != 0 {
+			return Zero, fmt.Errorf("expected %q to be 0 or 1 but found %s", negKey, neg)
+		}
+	}
+
+	if found < len(m) {
+		return Zero, fmt.Errorf("unexpected keys in period map %v", m)
+	}
+
+	p, err := NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+	if neg.Cmp(decimal.One) == 0 {
+		p = p.Negate()
+	}
+	return p, err
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// csvColumns is the number of CSV fields used by WriteToCSV and ReadFromCSV.
+const csvColumns = 8
+
+// WriteToCSV writes the period as one CSV record of eight fields, which suits tabular exports such
+// as audit logs and billing records. The first seven fields are the years, months, weeks, days,
+// hours, minutes and seconds, in that order, as decimal strings; zero fields are left empty. The
+// eighth field is "true" for negative periods and "false" otherwise. As for ToDecimalMap, the field
+// values exclude the overall sign, so "-P1M" gives ",1,,,,,,true".
+//
+// The writer is not flushed; the caller should call w.Flush when all records have been written.
+// See ReadFromCSV for the reverse conversion.
+func (period Period) WriteToCSV(w *csv.Writer) error {
+	record := make([]string, csvColumns)
+
+	abs := period.Abs()
+	for i, d := range FieldOrder() {
+		v := abs.GetField(d)
+		if !v.IsZero() {
+			record[i] = v.String()
+		}
+	}
+
+	record[csvColumns-1] = strconv.FormatBool(period.neg)
+	return w.Write(record)
+}
+
+// ReadFromCSV converts a CSV record written by WriteToCSV back to a period. Empty fields are
+// treated as zero, as is an empty sign field. An error arises if there are not exactly eight
+// fields, if any field cannot be parsed, or if more than one field has a fraction (see NewDecimal).
+func ReadFromCSV(record []string) (Period, error) {
+	if len(record) != csvColumns {
+		return Zero, fmt.Errorf("expected %d CSV fields for a period but found %d", csvColumns, len(record))
+	}
+
+	var fields [7]decimal.Decimal
+	for i, d := range FieldOrder() {
+		if record[i] != "" {
+			v, err := decimal.Parse(record[i])
+			if err != nil {
+				return Zero, fmt.Errorf("CSV %s field: %w", d, err)
+			}
+			fields[i] = v
+		}
+	}
+
+	neg := false
+	if record[csvColumns-1] != "" {
+		var err error
+		neg, err = strconv.ParseBool(record[csvColumns-1])
+		if err != nil {
+			return Zero, fmt.Errorf("CSV sign field: %w", err)
+		}
+	}
+
+	p, err := NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+	if neg {
+		p = p.Negate()
+	}
+	return p, err
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// firestoreKey is the map key that holds the ISO-8601 string in Firestore documents.
+const firestoreKey = "ISO"
+
+// MarshalFirestore converts the period to a map holding its ISO-8601 string under the key "ISO",
+// which is how Google Cloud Firestore stores custom types. The Firestore packages are not needed.
+func (period Period) MarshalFirestore() (interface{}, error) {
+	return map[string]interface{}{firestoreKey: formatISO(period, '.')}, nil
+}
+
+// UnmarshalFirestore reads a map created by MarshalFirestore back into the period. An error arises
+// if the "ISO" entry is missing, is not a string, or cannot be parsed.
+func (period *Period) UnmarshalFirestore(m map[string]interface{}) error {
+	v, exists := m[firestoreKey]
+	if !exists {
+		return fmt.Errorf("missing %q in Firestore period map %v", firestoreKey, m)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("%T %+v is not a meaningful period", v, v)
+	}
+
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*period = p
+	return nil
+}
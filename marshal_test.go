@@ -6,11 +6,14 @@ package period
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/govalues/decimal"
 	. "github.com/onsi/gomega"
 )
 
@@ -50,6 +53,29 @@ func TestGobEncoding(t *testing.T) {
 	}
 }
 
+func TestGobDecodeVersions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var p Period
+
+	bb, err := MustParse("P1Y2M").GobEncode()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bb).To(Equal(append([]byte{1}, "P1Y2M"...)))
+
+	err = p.GobDecode(bb)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("P1Y2M")))
+
+	// legacy format, without a version byte
+	err = p.GobDecode([]byte("-PT3H"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("-PT3H")))
+
+	err = p.GobDecode([]byte{9, 'P', '1', 'D'})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("unsupported gob encoding version 9 for a period"))
+}
+
 func TestISOStringJSONMarshalling(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -101,6 +127,11 @@ func TestPeriodJSONMarshalling(t *testing.T) {
 			bb, err := json.Marshal(c.value)
 			g.Expect(err).NotTo(HaveOccurred(), info(i, c))
 			g.Expect(string(bb)).To(Equal(c.want), info(i, c))
+
+			tj, err := c.value.ToJSON()
+			g.Expect(err).NotTo(HaveOccurred(), info(i, c))
+			g.Expect(tj).To(Equal(bb), info(i, c))
+
 			if string(bb) == c.want {
 				err = json.Unmarshal(bb, &p)
 				g.Expect(err).NotTo(HaveOccurred(), info(i, c))
@@ -163,3 +194,117 @@ func TestInvalidPeriodText(t *testing.T) {
 		})
 	}
 }
+
+func TestDecimalMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected map[string]decimal.Decimal
+	}{
+		{"P0D", map[string]decimal.Decimal{"neg": decimal.Zero}},
+		{"P1Y2M3W4DT5H6M7.5S", map[string]decimal.Decimal{
+			"years": decI(1), "months": decI(2), "weeks": decI(3), "days": decI(4),
+			"hours": decI(5), "minutes": decI(6), "seconds": dec(75, 1), "neg": decimal.Zero}},
+		{"-P1M", map[string]decimal.Decimal{"months": decI(1), "neg": decimal.One}},
+		{"P1M-1D", map[string]decimal.Decimal{"months": decI(1), "days": decI(-1), "neg": decimal.Zero}},
+		{"-P1M-1D", map[string]decimal.Decimal{"months": decI(1), "days": decI(-1), "neg": decimal.One}},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := MustParse(c.value)
+			m := p.ToDecimalMap()
+			g.Expect(m).To(Equal(c.expected), info(i, c.value))
+
+			p2, err := NewFromDecimalMap(m)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p2).To(Equal(p), info(i, c.value))
+		})
+	}
+
+	p, err := NewFromDecimalMap(map[string]decimal.Decimal{"hours": decI(2)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("PT2H")))
+
+	_, err = NewFromDecimalMap(map[string]decimal.Decimal{"hours": decI(2), "H": decI(1)})
+	g.Expect(err).To(MatchError("unexpected keys in period map map[H:1 hours:2]"))
+
+	_, err = NewFromDecimalMap(map[string]decimal.Decimal{"neg": decI(2)})
+	g.Expect(err).To(MatchError(`expected "neg" to be 0 or 1 but found 2`))
+}
+
+func TestCSV(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value string
+		line  string
+	}{
+		{"P0D", ",,,,,,,false"},
+		{"P1Y2M3W4DT5H6M7.5S", "1,2,3,4,5,6,7.5,false"},
+		{"-P1M", ",1,,,,,,true"},
+		{"P1M-1D", ",1,,-1,,,,false"},
+		{"-PT0.25S", ",,,,,,0.25,true"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p := MustParse(c.value)
+
+			buf := &strings.Builder{}
+			w := csv.NewWriter(buf)
+			g.Expect(p.WriteToCSV(w)).To(Succeed())
+			w.Flush()
+			g.Expect(buf.String()).To(Equal(c.line+"\n"), info(i, c.value))
+
+			record, err := csv.NewReader(strings.NewReader(buf.String())).Read()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			back, err := ReadFromCSV(record)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(back).To(Equal(p), info(i, c.value))
+		})
+	}
+
+	_, err := ReadFromCSV([]string{"1", "2"})
+	g.Expect(err).To(MatchError("expected 8 CSV fields for a period but found 2"))
+
+	_, err = ReadFromCSV([]string{"x", "", "", "", "", "", "", ""})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ReadFromCSV([]string{"", "", "", "1", "", "", "", "maybe"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ReadFromCSV([]string{"1.5", "", "", "1.5", "", "", "", ""})
+	g.Expect(err).To(HaveOccurred())
+}
+
+// firestoreValue mimics the interfaces that the Firestore client uses for custom types.
+type firestoreValue interface {
+	MarshalFirestore() (interface{}, error)
+}
+
+type firestoreTarget interface {
+	UnmarshalFirestore(map[string]interface{}) error
+}
+
+func TestFirestore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for i, v := range []string{"P0D", "P1Y2M3W4DT5H6M7.5S", "-P1M", "P1M-1D"} {
+		var fv firestoreValue = MustParse(v)
+		doc, err := fv.MarshalFirestore()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(doc).To(Equal(map[string]interface{}{"ISO": v}), info(i, v))
+
+		var p Period
+		var ft firestoreTarget = &p
+		g.Expect(ft.UnmarshalFirestore(doc.(map[string]interface{}))).To(Succeed())
+		g.Expect(p).To(Equal(MustParse(v)), info(i, v))
+	}
+
+	p := MustParse("P1D")
+	g.Expect(p.UnmarshalFirestore(map[string]interface{}{})).To(MatchError(`missing "ISO" in Firestore period map map[]`))
+	g.Expect(p.UnmarshalFirestore(map[string]interface{}{"ISO": 1})).To(MatchError("int 1 is not a meaningful period"))
+	g.Expect(p.UnmarshalFirestore(map[string]interface{}{"ISO": "P1X"})).To(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("P1D")))
+}
@@ -1,6 +1,8 @@
 package period
 
 import (
+	"time"
+
 	"github.com/govalues/decimal"
 )
 
@@ -11,6 +13,13 @@ var (
 	sixty       = decimal.MustNew(60, 0)
 	threeSixSix = decimal.MustNew(366, 0)
 	daysPerYear = decimal.MustNew(3652425, 4) // by the Gregorian rule
+
+	threeSixHundred       = decimal.MustNew(3600, 0)
+	daysPerMonth          = decimal.MustNew(daysPerMonthE6, 6)
+	secondsPerDayDecimal  = decimal.MustNew(secondsPerDay, 0)
+	nanosecondsPerSecond  = decimal.MustNew(int64(time.Second), 0)
+	microsecondsPerSecond = decimal.MustNew(int64(time.Second/time.Microsecond), 0)
+	millisecondsPerSecond = decimal.MustNew(int64(time.Second/time.Millisecond), 0)
 )
 
 // Normalise simplifies the fields by propagating large values towards the more significant fields.
@@ -43,6 +52,20 @@ func (period Period) Normalise(precise bool) Period {
 	return period
 }
 
+// IsNormalised returns true if Normalise(precise) would leave the period unaltered, i.e. no field
+// has a large value that Normalise would propagate towards the more significant fields.
+// For example, "PT59S" is normalised but "PT60S" is not.
+func (period Period) IsNormalised(precise bool) bool {
+	n := period.Normalise(precise)
+	return n.years.Cmp(period.years) == 0 &&
+		n.months.Cmp(period.months) == 0 &&
+		n.weeks.Cmp(period.weeks) == 0 &&
+		n.days.Cmp(period.days) == 0 &&
+		n.hours.Cmp(period.hours) == 0 &&
+		n.minutes.Cmp(period.minutes) == 0 &&
+		n.seconds.Cmp(period.seconds) == 0
+}
+
 // NormaliseDaysToYears tries to propagate large numbers of days (and corresponding weeks)
 // to the years field. Based on the Gregorian rule, there are assumed to be 365.2425 days per year.
 //
@@ -78,6 +101,46 @@ func (period Period) NormaliseDaysToYears() Period {
 	return period
 }
 
+// NormaliseHoursToMinutes is the inverse of normalisation for hours: when the minutes field is
+// non-zero, 60 * the hours field is added to it and the hours field is set to zero. For example,
+// "PT1H30M" becomes "PT90M". This is useful for representing precision at the lower unit.
+// Periods without minutes are returned unaltered, as are the other fields.
+//
+// If the calculations would lead to arithmetic errors, the current values are kept unaltered.
+func (period Period) NormaliseHoursToMinutes() Period {
+	period.hours, period.minutes = moveAllRight(period.hours, period.minutes, sixty)
+	return period.NormaliseSign()
+}
+
+// NormaliseMinutesToSeconds is the inverse of normalisation for minutes: when the seconds field
+// is non-zero, 60 * the minutes field is added to it and the minutes field is set to zero. For
+// example, "PT2M5S" becomes "PT125S". Periods without seconds are returned unaltered, as are the
+// other fields; chain this after NormaliseHoursToMinutes to expand hours into seconds as well.
+//
+// If the calculations would lead to arithmetic errors, the current values are kept unaltered.
+func (period Period) NormaliseMinutesToSeconds() Period {
+	period.minutes, period.seconds = moveAllRight(period.minutes, period.seconds, sixty)
+	return period.NormaliseSign()
+}
+
+func moveAllRight(larger, smaller, nd decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if larger.IsZero() || smaller.IsZero() {
+		return larger, smaller
+	}
+
+	extra, err := larger.Mul(nd)
+	if err != nil {
+		return larger, smaller
+	}
+
+	sm2, err := smaller.Add(extra)
+	if err != nil {
+		return larger, smaller
+	}
+
+	return decimal.Zero, sm2.Trim(0)
+}
+
 func moveWholePartsLeft(larger, smaller, nd decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
 	if smaller.IsZero() {
 		return larger, smaller
@@ -103,13 +166,27 @@ func moveWholePartsLeft(larger, smaller, nd decimal.Decimal) (decimal.Decimal, d
 //-------------------------------------------------------------------------------------------------
 
 // SimplifyWeeksToDays adds 7 * the weeks field to the days field, and sets the weeks field to zero.
+// If the calculations would lead to arithmetic errors, the period is returned unaltered.
 // See also SimplifyWeeks.
 func (period Period) SimplifyWeeksToDays() Period {
-	wdays, _ := period.weeks.Mul(seven)
-	days, _ := wdays.Add(period.days)
+	p, _ := period.simplifyWeeksToDays()
+	return p
+}
+
+func (period Period) simplifyWeeksToDays() (Period, bool) {
+	wdays, err := period.weeks.Mul(seven)
+	if err != nil {
+		return period, false
+	}
+
+	days, err := wdays.Add(period.days)
+	if err != nil {
+		return period, false
+	}
+
 	period.days = days
 	period.weeks = decimal.Zero
-	return period
+	return period, true
 }
 
 // SimplifyWeeks adds 7 * the weeks field to the days field, and sets the weeks field to zero,
@@ -121,12 +198,17 @@ func (period Period) SimplifyWeeksToDays() Period {
 //
 // See also SimplifyWeeksToDays.
 func (period Period) SimplifyWeeks() Period {
+	p, _ := period.simplifyWeeks()
+	return p
+}
+
+func (period Period) simplifyWeeks() (Period, bool) {
 	if period.years.Coef() != 0 || period.months.Coef() != 0 || period.days.Coef() != 0 ||
 		period.hours.Coef() != 0 || period.minutes.Coef() != 0 || period.seconds.Coef() != 0 {
 
-		return period.SimplifyWeeksToDays()
+		return period.simplifyWeeksToDays()
 	}
-	return period
+	return period, true
 }
 
 // Simplify simplifies the fields by propagating large values towards the less significant fields.
@@ -147,101 +229,127 @@ func (period Period) SimplifyWeeks() Period {
 //   - Minutes may become multiples of 60 seconds if the number of seconds is non-zero - both modes.
 //
 // If the calculations would lead to arithmetic errors, the current values are kept unaltered.
+// Use SimplifyChecked to find out whether this happened.
 func (period Period) Simplify(precise bool) Period {
-	period.years, period.months = moveToRight(period.years, period.months, twelve)
-	p2 := period.SimplifyWeeks() // more thorough
+	p, _ := period.SimplifyChecked(precise)
+	return p
+}
+
+// SimplifyChecked is as per Simplify but also returns a flag that is false if arithmetic overflow
+// prevented any of the steps, in which case the fields involved in that step are kept unaltered.
+// This allows callers to log the problem or take alternative action.
+func (period Period) SimplifyChecked(precise bool) (Period, bool) {
+	var ok1, ok2, ok3, ok4, ok5 bool
+	period.years, period.months, ok1 = moveToRight(period.years, period.months, twelve)
+	p2, ok2 := period.simplifyWeeks() // more thorough
+	ok3 = true
 	if !precise {
-		p2.days, p2.hours = moveToRight(p2.days, p2.hours, twentyFour)
+		p2.days, p2.hours, ok3 = moveToRight(p2.days, p2.hours, twentyFour)
 	}
-	p2.hours, p2.minutes = moveToRight(p2.hours, p2.minutes, sixty)
-	p2.minutes, p2.seconds = moveToRight(p2.minutes, p2.seconds, sixty)
-	return p2
+	p2.hours, p2.minutes, ok4 = moveToRight(p2.hours, p2.minutes, sixty)
+	p2.minutes, p2.seconds, ok5 = moveToRight(p2.minutes, p2.seconds, sixty)
+	return p2, ok1 && ok2 && ok3 && ok4 && ok5
 }
 
-func moveToRight(larger, smaller, nd decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+// moveToRight returns false only when arithmetic overflow prevented the move.
+func moveToRight(larger, smaller, nd decimal.Decimal) (decimal.Decimal, decimal.Decimal, bool) {
 	if larger.IsZero() || isSimple(larger, smaller) {
-		return larger, smaller
+		return larger, smaller, true
 	}
 
 	// first check whether it's actually simpler to keep things normalised
 	lg1, sm1 := moveWholePartsLeft(larger, smaller, nd)
 	if isSimple(lg1, sm1) {
-		return lg1, sm1 // it's hard to beat this
+		return lg1, sm1, true // it's hard to beat this
 	}
 
 	extra, err := larger.Mul(nd)
 	if err != nil {
-		return larger, smaller
+		return larger, smaller, false
 	}
 
 	sm2, err := smaller.Add(extra)
 	if err != nil {
-		return larger, smaller
+		return larger, smaller, false
 	}
 
 	sm2 = sm2.Trim(0)
 
 	originalDigits := larger.Prec() + smaller.Prec()
 	if sm2.Prec() > originalDigits {
-		return larger, smaller // because we would just add more digits
+		return larger, smaller, true // because we would just add more digits
 	}
 
-	return decimal.Zero, sm2
+	return decimal.Zero, sm2, true
 }
 
 func isSimple(larger, smaller decimal.Decimal) bool {
 	return smaller.IsZero() && larger.Scale() == 0
 }
 
-// normaliseSign swaps the signs of all fields so that the largest non-zero field is positive and the overall sign
-// indicates the original sign. Otherwise it has no effect.
-func (period Period) normaliseSign() Period {
+// NormaliseSign swaps the signs of all fields so that the largest non-zero field is positive and the overall sign
+// indicates the original sign. Otherwise it has no effect. The period still represents the same amount of time.
+// For example, "P-1Y1M" becomes "-P1Y-1M".
+//
+// Periods created by NewDecimal, Parse and the arithmetic methods are already sign-normalised, so this is
+// mainly useful after FlipSign.
+func (period Period) NormaliseSign() Period {
 	if period.years.Sign() > 0 {
 		return period
 	} else if period.years.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.months.Sign() > 0 {
 		return period
 	} else if period.months.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.weeks.Sign() > 0 {
 		return period
 	} else if period.weeks.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.days.Sign() > 0 {
 		return period
 	} else if period.days.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.hours.Sign() > 0 {
 		return period
 	} else if period.hours.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.minutes.Sign() > 0 {
 		return period
 	} else if period.minutes.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	if period.seconds.Sign() > 0 {
 		return period
 	} else if period.seconds.Sign() < 0 {
-		return period.flipSign()
+		return period.FlipSign()
 	}
 
 	return Zero
 }
 
-func (period Period) flipSign() Period {
+// FlipSign toggles the overall sign of the period and also negates every field, so the
+// period still represents the same amount of time; only its internal representation changes.
+// For example, "P1Y-1M" becomes "-P-1Y1M".
+//
+// This is a low-level primitive intended for code that implements its own normalisation;
+// see also NormaliseSign. Most callers should use Negate instead, which changes the value of the period.
+// Zero is not altered.
+func (period Period) FlipSign() Period {
+	if period.IsZero() {
+		return Zero
+	}
 	period.neg = !period.neg
 	return period.negateAllFields()
 }
@@ -256,3 +364,25 @@ func (period Period) negateAllFields() Period {
 	period.seconds = period.seconds.Neg()
 	return period
 }
+
+//-------------------------------------------------------------------------------------------------
+
+// Normalize is an alias for Normalise, using the American spelling.
+func (period Period) Normalize(precise bool) Period {
+	return period.Normalise(precise)
+}
+
+// IsNormalized is an alias for IsNormalised, using the American spelling.
+func (period Period) IsNormalized(precise bool) bool {
+	return period.IsNormalised(precise)
+}
+
+// NormalizeDaysToYears is an alias for NormaliseDaysToYears, using the American spelling.
+func (period Period) NormalizeDaysToYears() Period {
+	return period.NormaliseDaysToYears()
+}
+
+// NormalizeSign is an alias for NormaliseSign, using the American spelling.
+func (period Period) NormalizeSign() Period {
+	return period.NormaliseSign()
+}
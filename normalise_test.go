@@ -101,6 +101,39 @@ func Test_Normalise(t *testing.T) {
 	}
 }
 
+func Test_IsNormalised(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input     ISOString
+		precise   bool
+		imprecise bool
+	}{
+		// note: the negative cases are also covered (see below)
+
+		{input: "P0D", precise: true, imprecise: true},
+		{input: "P1Y11M6DT23H59M59S", precise: true, imprecise: true},
+		{input: "P12M", precise: false, imprecise: false},
+		{input: "P7D", precise: false, imprecise: false},
+		{input: "PT24H", precise: true, imprecise: false},
+		{input: "PT60M", precise: false, imprecise: false},
+		{input: "PT60S", precise: false, imprecise: false},
+		{input: "PT59.9S", precise: true, imprecise: true},
+		{input: "PT60.0005S", precise: true, imprecise: true}, // small overflow disregarded
+		{input: "P10W", precise: true, imprecise: true},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.input), func(t *testing.T) {
+			p := MustParse(c.input)
+			g.Expect(p.IsNormalised(true)).To(Equal(c.precise), "precise +ve case")
+			g.Expect(p.IsNormalised(false)).To(Equal(c.imprecise), "approximate +ve case")
+			g.Expect(p.Negate().IsNormalised(true)).To(Equal(c.precise), "precise -ve case")
+			g.Expect(p.Negate().IsNormalised(false)).To(Equal(c.imprecise), "approximate -ve case")
+		})
+	}
+}
+
 //-------------------------------------------------------------------------------------------------
 
 func Test_NormaliseDaysToYears(t *testing.T) {
@@ -146,6 +179,38 @@ func Test_NormaliseDaysToYears(t *testing.T) {
 
 //-------------------------------------------------------------------------------------------------
 
+func Test_NormaliseHoursToMinutes_MinutesToSeconds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input            ISOString
+		hoursToMinutes   ISOString
+		minutesToSeconds ISOString
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"PT1H", "PT1H", "PT1H"},
+		{"PT1H30M", "PT90M", "PT1H30M"},
+		{"PT2M5S", "PT2M5S", "PT125S"},
+		{"PT1H1M1S", "PT61M1S", "PT1H61S"},
+		{"PT1H30.5M", "PT90.5M", "PT1H30.5M"},
+		{"PT1M0.25S", "PT1M0.25S", "PT60.25S"},
+		{"PT1H-30M", "PT30M", "PT1H-30M"},
+		{"PT1H-90M", "-PT30M", "PT1H-90M"},
+		{"-PT1H30M", "-PT90M", "-PT1H30M"},
+		{"P1DT1H1M", "P1DT61M", "P1DT1H1M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.input), func(t *testing.T) {
+			p := MustParse(c.input)
+			g.Expect(p.NormaliseHoursToMinutes().Period()).To(Equal(c.hoursToMinutes), info(i, c.input))
+			g.Expect(p.NormaliseMinutesToSeconds().Period()).To(Equal(c.minutesToSeconds), info(i, c.input))
+		})
+	}
+
+	huge := Period{hours: dec(math.MaxInt64, 0), minutes: one}
+	g.Expect(huge.NormaliseHoursToMinutes()).To(Equal(huge))
+}
+
 func Test_SimplifyWeeksToDays(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -187,6 +252,17 @@ func Test_SimplifyWeeksToDays(t *testing.T) {
 
 //-------------------------------------------------------------------------------------------------
 
+func Test_SimplifyWeeksToDays_overflow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := Period{weeks: dec(math.MaxInt64/2, 0), days: one}
+	g.Expect(p.SimplifyWeeksToDays()).To(Equal(p))
+	g.Expect(p.SimplifyWeeks()).To(Equal(p))
+
+	_, ok := p.SimplifyChecked(true)
+	g.Expect(ok).To(BeFalse())
+}
+
 func Test_Simplify(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -259,6 +335,10 @@ func Test_Simplify(t *testing.T) {
 			sp1 := p1.Simplify(true)
 			g.Expect(sp1.Period()).To(Equal(c.precise), "precise +ve case")
 
+			sc1, ok := p1.SimplifyChecked(true)
+			g.Expect(sc1).To(Equal(sp1))
+			g.Expect(ok).To(Equal(c.input != extremeMinSec))
+
 			if !p1.IsZero() {
 				sp1n := p1.Negate().Simplify(true)
 				g.Expect(sp1n.Period()).To(Equal("-"+c.precise), "precise -ve case")
@@ -278,7 +358,7 @@ func Test_Simplify(t *testing.T) {
 
 //-------------------------------------------------------------------------------------------------
 
-func Test_normaliseSign(t *testing.T) {
+func Test_NormaliseSign(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	cases := []struct {
@@ -345,8 +425,51 @@ func Test_normaliseSign(t *testing.T) {
 
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("%d %s", i, c.expected), func(t *testing.T) {
-			sp1 := c.input.normaliseSign()
+			sp1 := c.input.NormaliseSign()
+			g.Expect(sp1.Period()).To(Equal(c.expected))
+		})
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func Test_FlipSign(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    Period
+		expected ISOString
+	}{
+		{input: Period{}, expected: "P0D"},
+		{input: Period{years: one}, expected: "-P-1Y"},
+		{input: Period{years: one, neg: true}, expected: "P-1Y"},
+		{input: Period{years: one, months: negOne}, expected: "-P-1Y1M"},
+		{input: Period{hours: one, minutes: negOne, seconds: one, neg: true}, expected: "PT-1H1M-1S"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.expected), func(t *testing.T) {
+			sp1 := c.input.FlipSign()
 			g.Expect(sp1.Period()).To(Equal(c.expected))
+			g.Expect(sp1.FlipSign()).To(Equal(c.input))
+			g.Expect(sp1.DurationApprox()).To(Equal(c.input.DurationApprox()))
 		})
 	}
 }
+
+//-------------------------------------------------------------------------------------------------
+
+func Test_American_spellings(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for i, v := range []string{"P0D", "PT90M", "P400D", "P14M", "P1DT25H", "P1Y1M"} {
+		p := MustParse(v)
+		g.Expect(p.Normalize(true)).To(Equal(p.Normalise(true)), info(i, v))
+		g.Expect(p.Normalize(false)).To(Equal(p.Normalise(false)), info(i, v))
+		g.Expect(p.IsNormalized(true)).To(Equal(p.IsNormalised(true)), info(i, v))
+		g.Expect(p.NormalizeDaysToYears()).To(Equal(p.NormaliseDaysToYears()), info(i, v))
+	}
+
+	p := Period{years: negOne, months: one}
+	g.Expect(p.NormalizeSign()).To(Equal(p.NormaliseSign()))
+}
@@ -5,7 +5,15 @@
 package period
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/govalues/decimal"
 )
 
@@ -26,12 +34,275 @@ func MustParse[S ISOString | string](isoPeriod S) Period {
 // It is possible to mix a number of weeks with other fields (e.g. P2M1W), although
 // this would not be allowed by ISO-8601. See SimplifyWeeks.
 //
+// Fields may also be given out of order (e.g. "P1D1Y"), except that a field that follows a less
+// significant one cannot have a fraction. ParseOptions.StrictISO rejects all out-of-order fields.
+//
 // The zero value can be represented in several ways: all of the following
 // are equivalent: "P0Y", "P0M", "P0W", "P0D", "PT0H", PT0M", PT0S", and "P0".
 // The canonical zero is "P0D".
+//
+// Decimal fractions may use either a full-stop or a comma, e.g. "P2.5Y" or "P2,5Y".
+//
+// Parse uses DefaultParseOptions; see ParseWith for more control.
 func Parse[S ISOString | string](isoPeriod S) (Period, error) {
+	return ParseWith(isoPeriod, DefaultParseOptions)
+}
+
+// ParseMultiple parses each of the strings as per Parse, e.g. for a list of periods read from
+// configuration. The result has the same length as ss; an entry that cannot be parsed is Zero.
+// All of the errors are combined using errors.Join, so the result is nil only if every string
+// was parsed successfully.
+func ParseMultiple(ss []string) ([]Period, error) {
+	periods := make([]Period, len(ss))
+	var errs []error
+	for i, s := range ss {
+		p, err := Parse(s)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			periods[i] = p
+		}
+	}
+	return periods, errors.Join(errs...)
+}
+
+// ParseFrom reads a period from r and parses it as per Parse. Leading whitespace is skipped, then
+// bytes are accumulated up to the next whitespace or the end of the input. The terminating
+// whitespace is consumed but nothing beyond it, so successive periods can be read from the same
+// stream, e.g. from a log file or a network protocol.
+//
+// If r is an io.ByteReader (e.g. a bufio.Reader) it is used directly; otherwise r is read one
+// byte at a time, so wrapping slow readers in a bufio.Reader is advisable.
+//
+// If r holds nothing more than whitespace, io.EOF is returned.
+func ParseFrom(r io.Reader) (Period, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &byteReader{r: r}
+	}
+
+	var buf []byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Zero, err
+		}
+
+		if isASCIISpace(b) {
+			if len(buf) == 0 {
+				continue
+			}
+			break
+		}
+		buf = append(buf, b)
+	}
+
+	if len(buf) == 0 {
+		return Zero, io.EOF
+	}
+	return Parse(string(buf))
+}
+
+func isASCIISpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+// ParseRelaxed parses strings that specify periods, as per Parse, but also accepts fractions in
+// more than one field, e.g. "P1.5Y2.5M", as produced by some legacy systems. Such fractions are
+// moved into the next less-significant field, so "P1.5Y2.5M" becomes "P1Y8.5M".
+//
+// Moving years to months, weeks to days, hours to minutes and minutes to seconds is exact.
+// Moving months to days (assuming 1/12 of 365.2425 days per month) and days to hours (assuming
+// 24 hours per day) is approximate. A Warning is returned for each fraction that was moved.
+func ParseRelaxed[S ISOString | string](isoPeriod S) (Period, []Warning, error) {
+	opts := DefaultParseOptions
+	opts.allowManyFractions = true
+
+	p, err := ParseWith(isoPeriod, opts)
+	if err != nil {
+		return p, nil, err
+	}
+
+	return p.carryFractions()
+}
+
+// Warning describes an adjustment made by ParseRelaxed to a fraction in the Field.
+type Warning struct {
+	Field       Designator
+	Into        Designator
+	Approximate bool
+}
+
+func (w Warning) String() string {
+	if w.Approximate {
+		return fmt.Sprintf("the fraction of %s was approximated as %s", w.Field, w.Into)
+	}
+	return fmt.Sprintf("the fraction of %s was converted to %s", w.Field, w.Into)
+}
+
+// fractionCarries lists, in FieldOrder, where each field's fraction can be moved to.
+var fractionCarries = []struct {
+	into        Designator
+	factor      decimal.Decimal
+	approximate bool
+}{
+	{Month, twelve, false},
+	{Day, daysPerMonth, true},
+	{Day, seven, false},
+	{Hour, twentyFour, true},
+	{Minute, sixty, false},
+	{Second, sixty, false},
+}
+
+func (period Period) carryFractions() (Period, []Warning, error) {
+	var fields [7]decimal.Decimal
+	order := FieldOrder()
+	for i, d := range order {
+		fields[i] = period.GetField(d)
+	}
+
+	var warnings []Warning
+	for i, c := range fractionCarries {
+		if fields[i].IsInt() || !anyNonZero(fields[i+1:]) {
+			continue
+		}
+
+		whole := fields[i].Trunc(0)
+		frac, _ := fields[i].Sub(whole)
+		j := int(Year - c.into)
+		sum, err := addMultiple(fields[j], frac, c.factor)
+		if err != nil {
+			return period, warnings, err
+		}
+
+		fields[i], fields[j] = whole, sum.Trim(0)
+		warnings = append(warnings, Warning{Field: order[i], Into: c.into, Approximate: c.approximate})
+	}
+
+	p, err := NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+	return p, warnings, err
+}
+
+func anyNonZero(fields []decimal.Decimal) bool {
+	for _, f := range fields {
+		if !f.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseISO8601 parses strings that specify periods either in the designator form accepted by
+// Parse, e.g. "P2Y10M15DT10H30M", or in the ISO-8601 alternative form, which has fixed-width
+// fields and no designators. The alternative form can be basic, e.g. "P00021015T103000", or
+// extended, e.g. "P0002-10-15T10:30:00". The time part is optional and the seconds may have a
+// fraction. As per ISO-8601, the alternative form cannot exceed 12 months, 30 days, 24 hours,
+// 59 minutes or 59 seconds.
+//
+// A leading plus or minus sign is allowed, as for Parse.
+func ParseISO8601[S ISOString | string](isoPeriod S) (Period, error) {
+	s := string(isoPeriod)
+	m := alternativeBasic.FindStringSubmatch(s)
+	if m == nil {
+		m = alternativeExtended.FindStringSubmatch(s)
+	}
+	if m == nil {
+		return Parse(s)
+	}
+
+	var fields [6]int
+	for i, limit := range []int{0, 12, 30, 24, 59, 59} {
+		if m[i+2] != "" {
+			fields[i], _ = strconv.Atoi(m[i+2])
+		}
+		if i > 0 && fields[i] > limit {
+			return Zero, fmt.Errorf("%s: %s cannot exceed %d in the alternative format", s, alternativeFields[i], limit)
+		}
+	}
+
+	seconds := decimal.MustNew(int64(fields[5]), 0)
+	if m[8] != "" {
+		seconds, _ = decimal.Parse(m[7] + "." + m[8][1:])
+	}
+
+	p, err := NewDecimal(decimal.MustNew(int64(fields[0]), 0), decimal.MustNew(int64(fields[1]), 0), decimal.Zero,
+		decimal.MustNew(int64(fields[2]), 0), decimal.MustNew(int64(fields[3]), 0), decimal.MustNew(int64(fields[4]), 0), seconds)
+	if m[1] == "-" {
+		p = p.Negate()
+	}
+	return p, err
+}
+
+var (
+	alternativeBasic    = regexp.MustCompile(`^([-+]?)P(\d{4})(\d{2})(\d{2})(?:T(\d{2})(\d{2})(\d{2})([.,]\d+)?)?$`)
+	alternativeExtended = regexp.MustCompile(`^([-+]?)P(\d{4})-(\d{2})-(\d{2})(?:T(\d{2}):(\d{2}):(\d{2})([.,]\d+)?)?$`)
+	alternativeFields   = []Designator{Year, Month, Day, Hour, Minute, Second}
+)
+
+// ParseAny parses strings that specify periods either using ISO-8601 rules (see Parse) or
+// in the format used by time.ParseDuration, e.g. "26h0m0s". The ISO-8601 form is tried first.
+//
+// Note that time.Duration strings hold only hours, minutes and seconds, so the result from
+// such a string never contains any calendar fields (years, months, weeks or days). The result
+// is not normalised; see Normalise.
+//
+// If neither format matches, the error contains both failures.
+func ParseAny[S ISOString | string](s S) (Period, error) {
+	p, err1 := Parse(s)
+	if err1 == nil {
+		return p, nil
+	}
+
+	d, err2 := time.ParseDuration(string(s))
+	if err2 == nil {
+		return NewOf(d), nil
+	}
+
+	return Zero, errors.Join(err1, err2)
+}
+
+// ParseOptions controls the leniency of ParseWith.
+type ParseOptions struct {
+	// AllowMixedSigns permits fields to have different signs, e.g. "P1M-1D".
+	AllowMixedSigns bool
+
+	// AllowLeadingWhitespace permits (and ignores) whitespace before the period.
+	AllowLeadingWhitespace bool
+
+	// AllowTrailingWhitespace permits (and ignores) whitespace after the period.
+	AllowTrailingWhitespace bool
+
+	// AllowCommaDecimal permits a comma to be used as the decimal separator, e.g. "P2,5Y".
+	// ISO-8601 allows both comma and full-stop.
+	AllowCommaDecimal bool
+
+	// StrictISO rejects all extensions to ISO-8601: a leading sign, negative fields, weeks
+	// mixed with other fields and fields out of order (e.g. "P1D1Y") are all treated as errors.
+	// AllowMixedSigns is ignored.
+	StrictISO bool
+
+	// allowManyFractions skips the check that only the last field has a fraction; see ParseRelaxed.
+	allowManyFractions bool
+}
+
+// DefaultParseOptions are the options used by Parse. Mixed signs and comma decimal separators
+// are allowed; whitespace is not.
+var DefaultParseOptions = ParseOptions{
+	AllowMixedSigns:   true,
+	AllowCommaDecimal: true,
+}
+
+// ParseWith parses strings that specify periods using ISO-8601 rules, as per Parse, but with
+// the leniency controlled by opts. This is useful for accepting user-supplied period strings.
+func ParseWith[S ISOString | string](isoPeriod S, opts ParseOptions) (Period, error) {
 	p := Period{}
-	err := p.Parse(string(isoPeriod))
+	err := p.parse(string(isoPeriod), opts)
 	return p, err
 }
 
@@ -46,6 +317,18 @@ func Parse[S ISOString | string](isoPeriod S) (Period, error) {
 // are equivalent: "P0Y", "P0M", "P0W", "P0D", "PT0H", PT0M", PT0S", and "P0".
 // The canonical zero is "P0D".
 func (period *Period) Parse(isoPeriod string) error {
+	return period.parse(isoPeriod, DefaultParseOptions)
+}
+
+func (period *Period) parse(isoPeriod string, opts ParseOptions) error {
+	if opts.AllowLeadingWhitespace {
+		isoPeriod = strings.TrimLeftFunc(isoPeriod, unicode.IsSpace)
+	}
+
+	if opts.AllowTrailingWhitespace {
+		isoPeriod = strings.TrimRightFunc(isoPeriod, unicode.IsSpace)
+	}
+
 	if isoPeriod == "" {
 		return fmt.Errorf(`cannot parse a blank string as a period`)
 	}
@@ -53,6 +336,10 @@ func (period *Period) Parse(isoPeriod string) error {
 	p := Zero
 
 	remaining := isoPeriod
+	if opts.StrictISO && (remaining[0] == '-' || remaining[0] == '+') {
+		return fmt.Errorf("%s: a leading sign is not allowed in strict ISO-8601", isoPeriod)
+	}
+
 	if remaining[0] == '-' {
 		p.neg = true
 		remaining = remaining[1:]
@@ -76,7 +363,7 @@ func (period *Period) Parse(isoPeriod string) error {
 	var haveFraction bool
 	var number decimal.Decimal
 	var years, months, weeks, days, hours, minutes, seconds itemState
-	var des, previous Designator
+	var des, previous, latest Designator
 	var err error
 	nComponents := 0
 
@@ -96,12 +383,24 @@ func (period *Period) Parse(isoPeriod string) error {
 			remaining = remaining[1:]
 
 		} else {
-			number, des, remaining, err = parseNextField(remaining, isoPeriod, isHMS)
+			number, des, remaining, err = parseNextField(remaining, isoPeriod, isHMS, opts)
 			if err != nil {
 				return err
 			}
 
-			if haveFraction && number.Coef() != 0 {
+			if latest != 0 && des > latest {
+				// out of order, which would otherwise bypass the fraction check below
+				if opts.StrictISO {
+					return fmt.Errorf("%s: '%c' designator cannot occur after '%c'", isoPeriod, des.Byte(), latest.Byte())
+				}
+				if number.Scale() > 0 && !opts.allowManyFractions {
+					return fmt.Errorf("%s: '%c' cannot have a fraction because it occurs after '%c'", isoPeriod, des.Byte(), latest.Byte())
+				}
+			} else {
+				latest = des
+			}
+
+			if haveFraction && number.Coef() != 0 && !opts.allowManyFractions {
 				return fmt.Errorf("%s: '%c' & '%c' only the last field can have a fraction", isoPeriod, previous.Byte(), des.Byte())
 			}
 
@@ -140,7 +439,15 @@ func (period *Period) Parse(isoPeriod string) error {
 		return fmt.Errorf("%s: expected 'Y', 'M', 'W', 'D', 'H', 'M', or 'S' designator", isoPeriod)
 	}
 
-	*period = p.normaliseSign()
+	if opts.StrictISO && weeks == set && nComponents > 1 {
+		return fmt.Errorf("%s: weeks cannot be mixed with other fields in strict ISO-8601", isoPeriod)
+	}
+
+	if !opts.AllowMixedSigns && !p.AllFieldsSameSign() {
+		return fmt.Errorf("%s: fields with mixed signs are not allowed", isoPeriod)
+	}
+
+	*period = p.NormaliseSign()
 	return nil
 }
 
@@ -168,8 +475,8 @@ func (i itemState) testAndSet(number decimal.Decimal, des Designator, result *de
 
 //-------------------------------------------------------------------------------------------------
 
-func parseNextField(str, original string, isHMS bool) (decimal.Decimal, Designator, string, error) {
-	number, i := scanDigits(str)
+func parseNextField(str, original string, isHMS bool, opts ParseOptions) (decimal.Decimal, Designator, string, error) {
+	number, i := scanDigits(str, opts)
 	switch i {
 	case noNumberFound:
 		return decimal.Zero, 0, "", fmt.Errorf("%s: expected a number but found '%c'", original, str[0])
@@ -191,14 +498,14 @@ func parseNextField(str, original string, isHMS bool) (decimal.Decimal, Designat
 }
 
 // scanDigits finds the index of the first non-digit character after some digits.
-func scanDigits(s string) (string, int) {
+func scanDigits(s string, opts ParseOptions) (string, int) {
 	rs := []rune(s)
 	number := make([]rune, 0, len(rs))
 
 	for i, c := range rs {
-		if i == 0 && c == '-' {
+		if i == 0 && c == '-' && !opts.StrictISO {
 			number = append(number, c)
-		} else if c == '.' || c == ',' {
+		} else if c == '.' || (c == ',' && opts.AllowCommaDecimal) {
 			number = append(number, '.') // next step needs decimal point not comma
 		} else if '0' <= c && c <= '9' {
 			number = append(number, c)
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"github.com/govalues/decimal"
 	. "github.com/onsi/gomega"
+	"io"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -34,6 +36,8 @@ func TestParseErrors(t *testing.T) {
 		{"P1HT1M", ": 'H' designator cannot occur here", "P1HT1M"},
 		{"PT1Y", ": 'Y' designator cannot occur here", "PT1Y"},
 		{"P1S", ": 'S' designator cannot occur here", "P1S"},
+		{"P1D,1Y", ": 'Y' cannot have a fraction because it occurs after 'D'", "P1D,1Y"},
+		{"PT1S0.5H", ": 'H' cannot have a fraction because it occurs after 'S'", "PT1S0.5H"},
 		{"P1D2D", ": 'D' designator cannot occur more than once", "P1D2D"},
 		{"PT1HT1S", ": 'T' designator cannot occur more than once", "PT1HT1S"},
 		{"P0.1YT0.1S", ": 'Y' & 'S' only the last field can have a fraction", "P0.1YT0.1S"},
@@ -158,6 +162,226 @@ func TestParsePeriod(t *testing.T) {
 			g.Expect(p).To(Equal(c.period), s)
 			// reversal is usually expected to be an identity
 			g.Expect(p.Period()).To(Equal(c.reversed), s+" reversed")
+
+			// round trip is always an identity
+			g.Expect(MustParse(p.FormatRoundTrip())).To(Equal(p), s+" round trip")
+			g.Expect(MustParse(p.Negate().FormatRoundTrip())).To(Equal(p.Negate()), s+" negated round trip")
+		})
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func TestParseWith(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	lenient := ParseOptions{AllowMixedSigns: true, AllowLeadingWhitespace: true, AllowTrailingWhitespace: true, AllowCommaDecimal: true}
+
+	cases := []struct {
+		value    string
+		opts     ParseOptions
+		expected ISOString
+	}{
+		{"P1Y2M", DefaultParseOptions, "P1Y2M"},
+		{"P2,5Y", DefaultParseOptions, "P2.5Y"},
+		{"P1M-1D", DefaultParseOptions, "P1M-1D"},
+		{" P1D", lenient, "P1D"},
+		{"P1D\t\n", lenient, "P1D"},
+		{"  -PT1H  ", lenient, "-PT1H"},
+		{"P1Y2M3DT4H5M6.7S", ParseOptions{StrictISO: true}, "P1Y2M3DT4H5M6.7S"},
+		{"P3W", ParseOptions{StrictISO: true}, "P3W"},
+		{"-P1D", ParseOptions{}, "-P1D"},
+		{"P1D1Y", DefaultParseOptions, "P1Y1D"},
+		{"PT1S1H", DefaultParseOptions, "PT1H1S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p, err := ParseWith(c.value, c.opts)
+			g.Expect(err).NotTo(HaveOccurred(), info(i, c.value))
+			g.Expect(p.Period()).To(Equal(c.expected), info(i, c.value))
+		})
+	}
+}
+
+func TestParseWithErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		opts     ParseOptions
+		expected string
+	}{
+		{" P1D", DefaultParseOptions, " P1D: expected 'P' period mark at the start"},
+		{"P1D ", DefaultParseOptions, "P1D : expected a number but found ' '"},
+		{"P2,5Y", ParseOptions{}, "P2,5Y: expected a designator Y, M, W, D, H, or S not ','"},
+		{"P1M-1D", ParseOptions{}, "P1M-1D: fields with mixed signs are not allowed"},
+		{"-P1D", ParseOptions{StrictISO: true}, "-P1D: a leading sign is not allowed in strict ISO-8601"},
+		{"P-1D", ParseOptions{StrictISO: true}, "P-1D: expected a number but found '-'"},
+		{"P2M1W", ParseOptions{StrictISO: true}, "P2M1W: weeks cannot be mixed with other fields in strict ISO-8601"},
+		{"P1D1Y", ParseOptions{StrictISO: true}, "P1D1Y: 'Y' designator cannot occur after 'D'"},
+		{"PT1S1H", ParseOptions{StrictISO: true}, "PT1S1H: 'H' designator cannot occur after 'S'"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			_, err := ParseWith(c.value, c.opts)
+			g.Expect(err).To(HaveOccurred(), info(i, c.value))
+			g.Expect(err.Error()).To(Equal(c.expected), info(i, c.value))
 		})
 	}
 }
+
+//-------------------------------------------------------------------------------------------------
+
+func TestParseMultiple(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ps, err := ParseMultiple([]string{"P1D", "P1W", "P1M"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ps).To(Equal([]Period{MustParse("P1D"), MustParse("P1W"), MustParse("P1M")}))
+
+	ps, err = ParseMultiple([]string{"P1X", "PT1H", "1D"})
+	g.Expect(err).To(MatchError("P1X: expected a designator Y, M, W, D, H, or S not 'X'\n1D: expected 'P' period mark at the start"))
+	g.Expect(ps).To(Equal([]Period{Zero, MustParse("PT1H"), Zero}))
+
+	ps, err = ParseMultiple(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ps).To(BeEmpty())
+}
+
+func TestParseFrom(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// MultiReader is not an io.ByteReader and splits one period across its parts
+	r := io.MultiReader(strings.NewReader("  P1Y2M\tPT3"), strings.NewReader("0M\n-P4D"))
+
+	p, err := ParseFrom(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("P1Y2M")))
+
+	p, err = ParseFrom(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("PT30M")))
+
+	p, err = ParseFrom(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("-P4D")))
+
+	_, err = ParseFrom(r)
+	g.Expect(err).To(Equal(io.EOF))
+
+	_, err = ParseFrom(strings.NewReader(" P1X "))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestParseRelaxed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+		warnings []Warning
+	}{
+		{"P0D", "P0D", nil},
+		{"P1.5Y", "P1.5Y", nil},
+		{"P1Y2M3DT4H5M6.5S", "P1Y2M3DT4H5M6.5S", nil},
+		{"P1.5Y2.5M", "P1Y8.5M", []Warning{{Year, Month, false}}},
+		{"P1.25Y1D", "P1Y3M1D", []Warning{{Year, Month, false}}},
+		{"P1.1Y1D", "P1Y1M7.087375D", []Warning{{Year, Month, false}, {Month, Day, true}}},
+		{"P1.5W1D", "P1W4.5D", []Warning{{Week, Day, false}}},
+		{"P1.5DT1H", "P1DT13H", []Warning{{Day, Hour, true}}},
+		{"PT1.5H1.5M1.5S", "PT1H31M31.5S", []Warning{{Hour, Minute, false}, {Minute, Second, false}}},
+		{"-PT1.5H1M", "-PT1H31M", []Warning{{Hour, Minute, false}}},
+		{"P1D0.5Y", "P6M1D", []Warning{{Year, Month, false}}},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p, warnings, err := ParseRelaxed(c.value)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.value))
+			g.Expect(warnings).To(Equal(c.warnings), info(i, c.value))
+		})
+	}
+
+	_, _, err := ParseRelaxed("P1X")
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(Warning{Year, Month, false}.String()).To(Equal("the fraction of years was converted to months"))
+	g.Expect(Warning{Day, Hour, true}.String()).To(Equal("the fraction of days was approximated as hours"))
+}
+
+func TestParseISO8601(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		// designator form
+		{"P0D", "P0D"},
+		{"P2Y10M15DT10H30M", "P2Y10M15DT10H30M"},
+		{"-P1W", "-P1W"},
+		// basic alternative form
+		{"P00000000", "P0D"},
+		{"P00021015T103000", "P2Y10M15DT10H30M"},
+		{"P00021015", "P2Y10M15D"},
+		{"P00000000T000001.5", "PT1.5S"},
+		{"P00000000T000001,5", "PT1.5S"},
+		{"-P00010000", "-P1Y"},
+		{"P00001230T240000", "P12M30DT24H"},
+		// extended alternative form
+		{"P0002-10-15T10:30:00", "P2Y10M15DT10H30M"},
+		{"P0002-10-15", "P2Y10M15D"},
+		{"+P0000-00-00T00:00:00.25", "PT0.25S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p, err := ParseISO8601(c.value)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.value))
+		})
+	}
+
+	errCases := []struct {
+		value    string
+		expected string
+	}{
+		{"P00001300", "P00001300: months cannot exceed 12 in the alternative format"},
+		{"P00000031", "P00000031: days cannot exceed 30 in the alternative format"},
+		{"P00000000T250000", "P00000000T250000: hours cannot exceed 24 in the alternative format"},
+		{"P0000-00-00T00:60:00", "P0000-00-00T00:60:00: minutes cannot exceed 59 in the alternative format"},
+		{"P0000000", "P0000000: missing designator at the end"},
+		{"P0000-00-00T0000", "P0000-00-00T0000: expected a designator Y, M, W, D, H, or S not '-'"},
+	}
+	for i, c := range errCases {
+		_, err := ParseISO8601(c.value)
+		g.Expect(err).To(MatchError(c.expected), info(i, c.value))
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected ISOString
+	}{
+		{"P1DT2H", "P1DT2H"},
+		{"-P3W", "-P3W"},
+		{"26h0m0s", "PT93600S"},
+		{"1m30.5s", "PT90.5S"},
+		{"-1.5h", "-PT5400S"},
+		{"0s", "P0D"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s", i, c.value), func(t *testing.T) {
+			p, err := ParseAny(c.value)
+			g.Expect(err).NotTo(HaveOccurred(), info(i, c.value))
+			g.Expect(p.Period()).To(Equal(c.expected), info(i, c.value))
+		})
+	}
+
+	_, err := ParseAny("3 days")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("3 days: expected 'P' period mark at the start"))
+	g.Expect(err.Error()).To(ContainSubstring(`time: unknown unit " days" in duration "3 days"`))
+}
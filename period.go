@@ -7,6 +7,7 @@ package period
 import (
 	"fmt"
 	"github.com/govalues/decimal"
+	"strings"
 	"time"
 )
 
@@ -38,6 +39,21 @@ type Period struct {
 // Zero is the zero period.
 var Zero = Period{}
 
+// Commonly-used periods. Go does not allow constants of struct types, so these are variables;
+// they must not be altered. The single units are prefixed with "One" because Week, Day, Hour,
+// Minute and Second are already the names of the field designators.
+var (
+	HalfYear  = Period{months: decimal.MustNew(6, 0)}
+	Quarter   = Period{months: decimal.MustNew(3, 0)}
+	OneYear   = Period{years: decimal.One}
+	OneMonth  = Period{months: decimal.One}
+	OneWeek   = Period{weeks: decimal.One}
+	OneDay    = Period{days: decimal.One}
+	OneHour   = Period{hours: decimal.One}
+	OneMinute = Period{minutes: decimal.One}
+	OneSecond = Period{seconds: decimal.One}
+)
+
 //-------------------------------------------------------------------------------------------------
 
 // NewYMD creates a simple period without any fractional parts. The fields are initialised verbatim
@@ -70,7 +86,7 @@ func New(years, months, weeks, days, hours, minutes, seconds int) Period {
 		days:    decimal.MustNew(int64(days), 0),
 		hours:   decimal.MustNew(int64(hours), 0),
 		minutes: decimal.MustNew(int64(minutes), 0),
-		seconds: decimal.MustNew(int64(seconds), 0)}.normaliseSign()
+		seconds: decimal.MustNew(int64(seconds), 0)}.NormaliseSign()
 }
 
 // MustNewDecimal creates a period from seven decimal values. The fields are trimmed but no normalisation
@@ -153,7 +169,7 @@ func NewDecimal(years, months, weeks, days, hours, minutes, seconds decimal.Deci
 		hours:   hours.Trim(0),
 		minutes: minutes.Trim(0),
 		seconds: seconds.Trim(0),
-	}.normaliseSign()
+	}.NormaliseSign()
 
 	if len(ymwd)+len(hms) > 0 {
 		err = fmt.Errorf("only the least significant field can have a fraction; found %s%s fractions in %s", string(ymwd), string(hms), p)
@@ -166,7 +182,42 @@ func NewDecimal(years, months, weeks, days, hours, minutes, seconds decimal.Deci
 // The result just a number of seconds, possibly including a fraction. It is not normalised; see Normalise.
 func NewOf(duration time.Duration) Period {
 	seconds := decimal.MustNew(int64(duration), 9).Trim(0)
-	return Period{seconds: seconds}.normaliseSign()
+	return Period{seconds: seconds}.NormaliseSign()
+}
+
+// NewOfYears creates a period of a number of years, possibly including a fraction.
+func NewOfYears(n decimal.Decimal) Period {
+	return Period{years: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfMonths creates a period of a number of months, possibly including a fraction.
+func NewOfMonths(n decimal.Decimal) Period {
+	return Period{months: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfWeeks creates a period of a number of weeks, possibly including a fraction.
+func NewOfWeeks(n decimal.Decimal) Period {
+	return Period{weeks: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfDays creates a period of a number of days, possibly including a fraction.
+func NewOfDays(n decimal.Decimal) Period {
+	return Period{days: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfHours creates a period of a number of hours, possibly including a fraction.
+func NewOfHours(n decimal.Decimal) Period {
+	return Period{hours: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfMinutes creates a period of a number of minutes, possibly including a fraction.
+func NewOfMinutes(n decimal.Decimal) Period {
+	return Period{minutes: n.Trim(0)}.NormaliseSign()
+}
+
+// NewOfSeconds creates a period of a number of seconds, possibly including a fraction.
+func NewOfSeconds(n decimal.Decimal) Period {
+	return Period{seconds: n.Trim(0)}.NormaliseSign()
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -189,6 +240,18 @@ func Between(t1, t2 time.Time) Period {
 	return NewOf(t1.Sub(t2)).Negate()
 }
 
+// Since returns the period elapsed since t, mirroring time.Since. It is shorthand for
+// Between(t, time.Now()), so the result is negative if t is in the future.
+func Since(t time.Time) Period {
+	return Between(t, time.Now())
+}
+
+// Until returns the period until t, mirroring time.Until. It is shorthand for
+// Between(time.Now(), t), so the result is negative if t is in the past.
+func Until(t time.Time) Period {
+	return Between(time.Now(), t)
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // IsZero returns true if applied to a period of zero length.
@@ -219,6 +282,78 @@ func (period Period) IsPositive() bool {
 	return !period.neg
 }
 
+// IsNeg is an alias for IsNegative.
+func (period Period) IsNeg() bool {
+	return period.IsNegative()
+}
+
+// IsPos is an alias for IsPositive; note that it is true for zero.
+func (period Period) IsPos() bool {
+	return period.IsPositive()
+}
+
+// AllFieldsSameSign returns true if every non-zero field has the same sign as the period as a whole.
+// It returns false for mixed-sign periods such as "P1M-1D", which some external systems reject.
+// The zero period returns true.
+func (period Period) AllFieldsSameSign() bool {
+	sign := 0
+	for _, f := range []decimal.Decimal{period.years, period.months, period.weeks, period.days, period.hours, period.minutes, period.seconds} {
+		switch {
+		case f.Sign() == 0:
+			// ignored
+		case sign == 0:
+			sign = f.Sign()
+		case f.Sign() != sign:
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset returns true if every field of the period is no larger in magnitude than the
+// corresponding field of other, and has the same sign (zero fields are always acceptable).
+// For example, "P1DT2H" is a subset of "P3DT2H30M" but not of "P3D" nor of "-P3DT2H".
+//
+// This is a field-wise comparison, not a duration-wise one: "PT90M" is not a subset of "PT2H".
+// See Compare for the latter.
+func (period Period) IsSubset(other Period) bool {
+	for _, d := range FieldOrder() {
+		mine, theirs := period.GetField(d), other.GetField(d)
+		if mine.IsZero() {
+			continue
+		}
+		if mine.Sign() != theirs.Sign() || mine.CmpAbs(theirs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsWholeNumber returns true if no field contains a non-zero fraction. Such periods are safe to pass
+// to systems that do not support fractional ISO-8601 durations.
+func (period Period) IsWholeNumber() bool {
+	return period.years.IsInt() && period.months.IsInt() && period.weeks.IsInt() && period.days.IsInt() &&
+		period.hours.IsInt() && period.minutes.IsInt() && period.seconds.IsInt()
+}
+
+// IsDecimalSafe returns true if every field can be converted to float64 and back without any loss
+// of precision, e.g. before serialising the fields to JavaScript clients or other consumers that
+// use float64 numbers. For example, 0.1 is safe but 12345678901234567 is not.
+func (period Period) IsDecimalSafe() bool {
+	return isFloat64Safe(period.years) && isFloat64Safe(period.months) && isFloat64Safe(period.weeks) &&
+		isFloat64Safe(period.days) && isFloat64Safe(period.hours) && isFloat64Safe(period.minutes) &&
+		isFloat64Safe(period.seconds)
+}
+
+func isFloat64Safe(d decimal.Decimal) bool {
+	f, ok := d.Float64()
+	if !ok {
+		return false
+	}
+	back, err := decimal.NewFromFloat64(f)
+	return err == nil && back.Cmp(d) == 0
+}
+
 // Abs converts a negative period to a positive period.
 func (period Period) Abs() Period {
 	period.neg = false
@@ -234,6 +369,18 @@ func (period Period) Negate() Period {
 	return period
 }
 
+// SetNeg sets the overall sign of the period: negative if neg is true, otherwise positive.
+// Unlike Negate, the result does not depend on the existing sign, which is useful when building
+// periods from sign-separated components. The fields themselves are not altered, so
+// SetNeg(true) on "P1M-1D" gives "-P1M-1D". Zero is not altered.
+func (period Period) SetNeg(neg bool) Period {
+	if period.IsZero() {
+		return Zero
+	}
+	period.neg = neg
+	return period
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // OnlyYMWD returns the period with only the year, month, week and day fields.
@@ -248,6 +395,52 @@ func (period Period) OnlyHMS() Period {
 	return Period{neg: period.neg, hours: period.hours, minutes: period.minutes, seconds: period.seconds}
 }
 
+// Designators lists the designators of the non-zero fields, in the order given by FieldOrder.
+// For example, "P1Y2DT3S" gives Year, Day, Second. The zero period gives an empty list. This
+// suits formatting code that needs to know which fields are present before fetching their values,
+// e.g. using GetField.
+func (period Period) Designators() []Designator {
+	var list []Designator
+	for _, d := range FieldOrder() {
+		if !period.GetField(d).IsZero() {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// ReduceToSingleField returns the only non-zero field of the period and its signed value, e.g.
+// "P3M" gives Month and 3 and "-PT5M" gives Minute and -5. This suits validation code that
+// expects a single-unit period, such as "retry every N minutes".
+//
+// An error arises if the period is zero or if it has more than one non-zero field.
+func (period Period) ReduceToSingleField() (Designator, decimal.Decimal, error) {
+	list := period.Designators()
+	switch len(list) {
+	case 0:
+		return 0, decimal.Zero, fmt.Errorf("%s has no non-zero fields", period)
+	case 1:
+		return list[0], period.GetField(list[0]), nil
+	}
+
+	names := make([]string, len(list))
+	for i, d := range list {
+		names[i] = d.String()
+	}
+	return 0, decimal.Zero, fmt.Errorf("%s has more than one non-zero field: %s", period, strings.Join(names, ", "))
+}
+
+// MustReduce is as per ReduceToSingleField except that it panics if the period does not have
+// exactly one non-zero field. This is intended for periods that are known to have a single field,
+// e.g. because they come from a configuration that enforces it.
+func (period Period) MustReduce() (Designator, decimal.Decimal) {
+	d, v, err := period.ReduceToSingleField()
+	if err != nil {
+		panic(err)
+	}
+	return d, v
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // Years gets the whole number of years in the period.
@@ -313,6 +506,14 @@ func (period Period) DaysIncWeeksDecimal() decimal.Decimal {
 	return period.applySign(days)
 }
 
+// DaysIncWeeksFloat64 gets the number of days in the period, including all the weeks and including
+// any fraction present, as a float64. This is convenient for charts etc where an approximate value
+// is sufficient.
+func (period Period) DaysIncWeeksFloat64() float64 {
+	f, _ := period.DaysIncWeeksDecimal().Float64()
+	return f
+}
+
 // Hours gets the whole number of hours in the period.
 func (period Period) Hours() int {
 	i, _, _ := period.HoursDecimal().Int64(0)
@@ -324,6 +525,12 @@ func (period Period) HoursDecimal() decimal.Decimal {
 	return period.applySign(period.hours)
 }
 
+// HoursFloat64 gets the number of hours in the period, including any fraction present, as a float64.
+func (period Period) HoursFloat64() float64 {
+	f, _ := period.HoursDecimal().Float64()
+	return f
+}
+
 // Minutes gets the whole number of minutes in the period.
 func (period Period) Minutes() int {
 	i, _, _ := period.MinutesDecimal().Int64(0)
@@ -335,6 +542,12 @@ func (period Period) MinutesDecimal() decimal.Decimal {
 	return period.applySign(period.minutes)
 }
 
+// MinutesFloat64 gets the number of minutes in the period, including any fraction present, as a float64.
+func (period Period) MinutesFloat64() float64 {
+	f, _ := period.MinutesDecimal().Float64()
+	return f
+}
+
 // Seconds gets the whole number of seconds in the period.
 func (period Period) Seconds() int {
 	i, _, _ := period.SecondsDecimal().Int64(0)
@@ -346,6 +559,12 @@ func (period Period) SecondsDecimal() decimal.Decimal {
 	return period.applySign(period.seconds)
 }
 
+// SecondsFloat64 gets the number of seconds in the period, including any fraction present, as a float64.
+func (period Period) SecondsFloat64() float64 {
+	f, _ := period.SecondsDecimal().Float64()
+	return f
+}
+
 func (period Period) applySign(field decimal.Decimal) decimal.Decimal {
 	if period.neg {
 		return field.Neg()
@@ -400,10 +619,15 @@ func (period Period) SetInt(value int, field Designator) Period {
 }
 
 // SetField sets one field in the period. Like NewDecimal, an error arises if the new period
-// would have multiple fields with fractions.
+// would have multiple fields with fractions. The value has the same sign convention as
+// GetField, i.e. it is negative for negative fields.
 //
 // A panic arises if the field is unknown.
 func (period Period) SetField(value decimal.Decimal, field Designator) (Period, error) {
+	if period.neg {
+		period = period.FlipSign() // now the fields hold their signed values
+	}
+
 	switch field {
 	case Year:
 		return NewDecimal(value, period.months, period.weeks, period.days, period.hours, period.minutes, period.seconds)
@@ -423,3 +647,186 @@ func (period Period) SetField(value decimal.Decimal, field Designator) (Period,
 
 	panic(field)
 }
+
+//-------------------------------------------------------------------------------------------------
+
+// NegateField changes the sign of one field in the period, leaving the others unaltered.
+// For example, negating the days of "P1M1D" gives "P1M-1D". Like SetField, an error arises
+// if the new period would have multiple fields with fractions.
+//
+// A panic arises if the field is unknown.
+func (period Period) NegateField(field Designator) (Period, error) {
+	return period.SetField(period.GetField(field).Neg(), field)
+}
+
+// MultiplyField multiplies one field in the period by a factor, leaving the others unaltered.
+// For example, multiplying the months of "P1Y2M" by 2 gives "P1Y4M". Like SetField, an error
+// arises if the new period would have multiple fields with fractions, and also if there is
+// arithmetic overflow.
+//
+// A panic arises if the field is unknown.
+func (period Period) MultiplyField(field Designator, factor decimal.Decimal) (Period, error) {
+	value, err := period.GetField(field).Mul(factor)
+	if err != nil {
+		return period, err
+	}
+	return period.SetField(value, field)
+}
+
+// ScaleField is an alias for MultiplyField; for example ScaleField(Month, decimal.Two) doubles
+// the months.
+func (period Period) ScaleField(field Designator, factor decimal.Decimal) (Period, error) {
+	return period.MultiplyField(field, factor)
+}
+
+// DivideField divides one field in the period by a divisor, leaving the others unaltered.
+// For example, dividing the months of "P1Y4M" by 2 gives "P1Y2M". An error arises if the divisor
+// is zero or if the new period would have multiple fields with fractions, e.g. dividing the days
+// of "P3DT1H" by 2.
+//
+// A panic arises if the field is unknown.
+func (period Period) DivideField(field Designator, divisor decimal.Decimal) (Period, error) {
+	if divisor.IsZero() {
+		return period, fmt.Errorf("cannot divide the %s of %s by zero", field, period)
+	}
+
+	value, err := period.GetField(field).Quo(divisor)
+	if err != nil {
+		return period, err
+	}
+	return period.SetField(value, field)
+}
+
+// AddField adds a value to one field in the period, leaving the others unaltered. For example,
+// AddField(decimal.One, Month) on "P1Y2M" gives "P1Y3M". Like SetField, the value has the same
+// sign convention as GetField and an error arises if the new period would have multiple fields
+// with fractions, and also if there is arithmetic overflow.
+//
+// A panic arises if the field is unknown.
+func (period Period) AddField(value decimal.Decimal, field Designator) (Period, error) {
+	sum, err := period.GetField(field).Add(value)
+	if err != nil {
+		return period, err
+	}
+	return period.SetField(sum, field)
+}
+
+// SubtractField subtracts a value from one field in the period, leaving the others unaltered.
+// It is equivalent to AddField(value.Neg(), field).
+//
+// A panic arises if the field is unknown.
+func (period Period) SubtractField(value decimal.Decimal, field Designator) (Period, error) {
+	return period.AddField(value.Neg(), field)
+}
+
+// Merge combines two periods field by field, in the manner of SQL COALESCE. Each field in the
+// result is taken from other if it is non-zero there, otherwise from the receiver. This is useful
+// when building up a period from separate sources, e.g. the date part from one and the time part
+// from another.
+//
+// An error arises if any field is non-zero in both periods, or if the result would have
+// multiple fields with fractions (see NewDecimal).
+func (period Period) Merge(other Period) (Period, error) {
+	var fields [7]decimal.Decimal
+	var conflicts []string
+
+	for i, d := range FieldOrder() {
+		mine, theirs := period.GetField(d), other.GetField(d)
+		switch {
+		case theirs.IsZero():
+			fields[i] = mine
+		case mine.IsZero():
+			fields[i] = theirs
+		default:
+			conflicts = append(conflicts, d.String())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return period, fmt.Errorf("cannot merge %s with %s: both have non-zero %s", period, other, strings.Join(conflicts, ", "))
+	}
+
+	return NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+}
+
+// Intersect returns a period in which each field is whichever of the two corresponding fields
+// is smaller in magnitude. This is useful for enforcing a cap on each field separately, e.g.
+// the intersection of "P3DT1H" and "P1DT5H" is "P1DT1H".
+//
+// An error arises if any field is positive in one period and negative in the other, or if the
+// result would have multiple fields with fractions (see NewDecimal).
+func (period Period) Intersect(other Period) (Period, error) {
+	return period.combineFields(other, "intersect", func(a, b decimal.Decimal) decimal.Decimal {
+		if a.CmpAbs(b) <= 0 {
+			return a
+		}
+		return b
+	})
+}
+
+// Union returns a period in which each field is whichever of the two corresponding fields
+// is larger in magnitude. This is useful for merging two estimates into a bounding period, e.g.
+// the union of "P3DT1H" and "P1DT5H" is "P3DT5H".
+//
+// An error arises if any field is positive in one period and negative in the other, or if the
+// result would have multiple fields with fractions (see NewDecimal).
+func (period Period) Union(other Period) (Period, error) {
+	return period.combineFields(other, "union", func(a, b decimal.Decimal) decimal.Decimal {
+		if a.CmpAbs(b) >= 0 {
+			return a
+		}
+		return b
+	})
+}
+
+// combineFields builds a period by picking between the corresponding fields of two periods.
+func (period Period) combineFields(other Period, verb string, pick func(a, b decimal.Decimal) decimal.Decimal) (Period, error) {
+	var fields [7]decimal.Decimal
+	var conflicts []string
+
+	for i, d := range FieldOrder() {
+		mine, theirs := period.GetField(d), other.GetField(d)
+		if mine.Sign()*theirs.Sign() < 0 {
+			conflicts = append(conflicts, d.String())
+		}
+		fields[i] = pick(mine, theirs)
+	}
+
+	if len(conflicts) > 0 {
+		return period, fmt.Errorf("cannot %s %s with %s: opposite signs in %s", verb, period, other, strings.Join(conflicts, ", "))
+	}
+
+	return NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Repeat returns a slice containing n copies of the period, e.g. for generating a number of
+// instalments. The slice is empty if n is not positive.
+func (period Period) Repeat(n int) []Period {
+	return period.RepeatWith(n, func(_ int, p Period) Period { return p })
+}
+
+// RepeatWith returns a slice containing n copies of the period, each transformed by f, which is
+// given the zero-based index of the copy. The slice is empty if n is not positive.
+func (period Period) RepeatWith(n int, f func(i int, p Period) Period) []Period {
+	list := make([]Period, max(n, 0))
+	for i := range list {
+		list[i] = f(i, period)
+	}
+	return list
+}
+
+// Apply passes the period through each function in turn, returning the final result. This allows
+// transformation pipelines to be composed, e.g.
+//
+//	p.Apply(
+//		func(p Period) Period { return p.Normalise(false) },
+//		func(p Period) Period { return p.Simplify(false) },
+//	)
+func (period Period) Apply(fns ...func(Period) Period) Period {
+	for _, fn := range fns {
+		period = fn(period)
+	}
+	return period
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"P0D", "P1Y2M3W4DT5H6M7.5S", "-P1Y", "+PT1H", "P1M-1D", "PT1,5S", "P-1Y1M", "-PT0.000000001S"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		p, err := Parse(s)
+		if err != nil {
+			return
+		}
+
+		str := p.String()
+		p2, err := Parse(str)
+		if err != nil {
+			t.Fatalf("%q parsed as %s, which did not re-parse: %v", s, str, err)
+		}
+
+		// the internal representation may differ, e.g. "P1.0D" has a zero fraction
+		if p2.String() != str || p2.Compare(p) != 0 {
+			t.Fatalf("%q parsed as %s, which re-parsed as %s", s, str, p2)
+		}
+	})
+}
+
+func FuzzArithmetic(f *testing.F) {
+	f.Add(1, 2, 3, 4, 5, 6, 7, 7, 6, 5, 4, 3, 2, 1)
+	f.Add(0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1)
+	f.Add(-1, 11, 0, 0, 23, 59, 59, 1, 1, 0, 0, 1, 1, 1)
+
+	f.Fuzz(func(t *testing.T, y1, m1, w1, d1, hh1, mm1, ss1, y2, m2, w2, d2, hh2, mm2, ss2 int) {
+		p := New(y1, m1, w1, d1, hh1, mm1, ss1)
+		q := New(y2, m2, w2, d2, hh2, mm2, ss2)
+
+		sum, err := p.Add(q)
+		if err != nil {
+			return
+		}
+
+		r, err := sum.Subtract(q)
+		if err != nil {
+			return
+		}
+
+		if r.Compare(p) != 0 {
+			t.Fatalf("%s + %s - %s gave %s", p, q, q, r)
+		}
+	})
+}
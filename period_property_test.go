@@ -0,0 +1,103 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"testing"
+
+	"github.com/govalues/decimal"
+	"pgregory.net/rapid"
+)
+
+// genPeriod generates valid periods with mixed signs; only the least significant non-zero
+// field may have a fraction.
+var genPeriod = rapid.Custom(func(t *rapid.T) Period {
+	var fields [7]decimal.Decimal
+	last := -1
+	for i := range fields {
+		n := rapid.OneOf(rapid.Just(0), rapid.IntRange(-1000, 1000)).Draw(t, "field")
+		fields[i] = decimal.MustNew(int64(n), 0)
+		if n != 0 {
+			last = i
+		}
+	}
+
+	if last >= 0 {
+		frac := rapid.IntRange(0, 999).Draw(t, "fraction")
+		f := decimal.MustNew(int64(frac), 3)
+		if fields[last].Sign() < 0 {
+			f = f.Neg()
+		}
+		fields[last], _ = fields[last].Add(f)
+	}
+
+	p, err := NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rapid.Bool().Draw(t, "negate") {
+		p = p.Negate()
+	}
+	return p
+})
+
+func TestProperty_ParseString(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		p := genPeriod.Draw(t, "p")
+		p2, err := Parse(p.String())
+		if err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+		if p2 != p {
+			t.Fatalf("%s re-parsed as %s", p, p2)
+		}
+	})
+}
+
+func TestProperty_DoubleNegation(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		p := genPeriod.Draw(t, "p")
+		if p.Negate().Negate() != p {
+			t.Fatalf("%s negated twice gave %s", p, p.Negate().Negate())
+		}
+	})
+}
+
+func TestProperty_AbsSign(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		p := genPeriod.Draw(t, "p")
+		if p.Abs().Sign() < 0 {
+			t.Fatalf("%s has negative Abs %s", p, p.Abs())
+		}
+	})
+}
+
+func TestProperty_AddZero(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		p := genPeriod.Draw(t, "p")
+		sum, err := p.Add(Zero)
+		if err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+		// Add normalises its result, so only the value is unaltered, not the representation
+		if sum.Compare(p) != 0 || sum != p.Normalise(true).NormaliseSign() {
+			t.Fatalf("%s + 0 gave %s", p, sum)
+		}
+	})
+}
+
+func TestProperty_MulOne(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		p := genPeriod.Draw(t, "p")
+		product, err := p.Mul(decimal.One)
+		if err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+		if product != p {
+			t.Fatalf("%s * 1 gave %s", p, product)
+		}
+	})
+}
@@ -232,6 +232,173 @@ func TestSetGet(t *testing.T) {
 	}
 }
 
+func TestSetField_negative(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p0 := MustParse("-P1Y2M")
+
+	p1, err := p0.SetField(decI(-10), Year)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p1).To(Equal(MustParse("-P10Y2M")))
+	g.Expect(p1.GetField(Year)).To(Equal(decI(-10)))
+
+	p2 := p0.SetInt(5, Day)
+	g.Expect(p2).To(Equal(MustParse("-P1Y2M-5D")))
+	g.Expect(p2.GetInt(Day)).To(Equal(5))
+}
+
+func TestNegateField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    string
+		field    Designator
+		expected string
+	}{
+		{"P0D", Day, "P0D"},
+		{"P1D", Day, "-P1D"},
+		{"P1M1D", Day, "P1M-1D"},
+		{"P1M-1D", Day, "P1M1D"},
+		{"P1M1D", Month, "-P1M-1D"},
+		{"-P1M1D", Day, "-P1M-1D"},
+		{"P1Y2M3W4DT5H6M7.5S", Second, "P1Y2M3W4DT5H6M-7.5S"},
+		{"P1Y2M3W4DT5H6M7S", Hour, "P1Y2M3W4DT-5H6M7S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %c", i, c.input, c.field.Byte()), func(t *testing.T) {
+			p, err := MustParse(c.input).NegateField(c.field)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+}
+
+func TestMultiplyField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    string
+		field    Designator
+		factor   decimal.Decimal
+		expected string
+	}{
+		{"P0D", Day, decI(2), "P0D"},
+		{"P1Y2M", Month, decI(2), "P1Y4M"},
+		{"P1Y2M", Year, decI(3), "P3Y2M"},
+		{"P1Y2M", Day, decI(3), "P1Y2M"},
+		{"-P1Y2M", Month, decI(2), "-P1Y4M"},
+		{"P1M1D", Day, decI(-1), "P1M-1D"},
+		{"P1D", Day, decI(-2), "-P2D"},
+		{"PT1H30M", Minute, dec(5, 1), "PT1H15M"},
+		{"PT1H1M", Minute, dec(5, 1), "PT1H0.5M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %c", i, c.input, c.field.Byte()), func(t *testing.T) {
+			p, err := MustParse(c.input).MultiplyField(c.field, c.factor)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+
+			p, err = MustParse(c.input).ScaleField(c.field, c.factor)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+
+	_, err := MustParse("P1DT1H").MultiplyField(Day, dec(5, 1))
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = MustParse("P1DT1H").ScaleField(Day, dec(5, 1))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestDivideField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    string
+		field    Designator
+		divisor  decimal.Decimal
+		expected string
+	}{
+		{"P0D", Day, decI(2), "P0D"},
+		{"P1Y4M", Month, decI(2), "P1Y2M"},
+		{"P3D", Day, decI(2), "P1.5D"},
+		{"-P3D", Day, decI(2), "-P1.5D"},
+		{"P1M2D", Day, decI(-1), "P1M-2D"},
+		{"PT1H30M", Minute, dec(5, 1), "PT1H60M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %c", i, c.input, c.field.Byte()), func(t *testing.T) {
+			p, err := MustParse(c.input).DivideField(c.field, c.divisor)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+
+	_, err := MustParse("P3D").DivideField(Day, decimal.Zero)
+	g.Expect(err).To(MatchError("cannot divide the days of P3D by zero"))
+
+	_, err = MustParse("P3DT1H").DivideField(Day, decI(2))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestAddField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    string
+		value    decimal.Decimal
+		field    Designator
+		expected string
+	}{
+		{"P0D", decI(2), Day, "P2D"},
+		{"P1Y2M", decimal.One, Month, "P1Y3M"},
+		{"P1Y2M", decI(-2), Month, "P1Y"},
+		{"P1Y2M", decimal.One, Hour, "P1Y2MT1H"},
+		{"-P1Y2M", decimal.One, Month, "-P1Y1M"},
+		{"P1D", decI(-3), Day, "-P2D"},
+		{"PT1H", dec(5, 1), Minute, "PT1H0.5M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %c", i, c.input, c.field.Byte()), func(t *testing.T) {
+			p, err := MustParse(c.input).AddField(c.value, c.field)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+
+	_, err := MustParse("PT1H").AddField(dec(5, 1), Day)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSubtractField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		input    string
+		value    decimal.Decimal
+		field    Designator
+		expected string
+	}{
+		{"P0D", decI(2), Day, "-P2D"},
+		{"P1Y2M", decimal.One, Month, "P1Y1M"},
+		{"P1Y2M", decI(2), Month, "P1Y"},
+		{"P1Y2M", decimal.One, Hour, "P1Y2MT-1H"},
+		{"-P1Y2M", decimal.One, Month, "-P1Y3M"},
+		{"PT1H", dec(5, 1), Minute, "PT1H-0.5M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %c", i, c.input, c.field.Byte()), func(t *testing.T) {
+			p, err := MustParse(c.input).SubtractField(c.value, c.field)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+
+	_, err := MustParse("PT1H").SubtractField(dec(5, 1), Day)
+	g.Expect(err).To(HaveOccurred())
+}
+
 //-------------------------------------------------------------------------------------------------
 
 func TestNewDecimal(t *testing.T) {
@@ -453,6 +620,29 @@ func testNewOf1(t *testing.T, i int, source time.Duration, expected Period) {
 	g.Expect(rev).To(Equal(source), info)
 }
 
+func TestNewOfSingleFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		fn       func(decimal.Decimal) Period
+		n        decimal.Decimal
+		expected ISOString
+	}{
+		{NewOfYears, decI(2), "P2Y"},
+		{NewOfMonths, decI(3), "P3M"},
+		{NewOfWeeks, decI(4), "P4W"},
+		{NewOfDays, decS("5.50"), "P5.5D"},
+		{NewOfHours, decI(6), "PT6H"},
+		{NewOfMinutes, decI(-7), "-PT7M"},
+		{NewOfSeconds, decS("8.25"), "PT8.25S"},
+		{NewOfDays, decimal.Zero, "P0D"},
+	}
+	for i, c := range cases {
+		p := c.fn(c.n)
+		g.Expect(p.Period()).To(Equal(c.expected), info(i, c.expected))
+	}
+}
+
 //-------------------------------------------------------------------------------------------------
 
 func TestBetween(t *testing.T) {
@@ -524,6 +714,35 @@ func TestBetween(t *testing.T) {
 	}
 }
 
+func TestCommonPeriods(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(HalfYear).To(Equal(MustParse("P6M")))
+	g.Expect(Quarter).To(Equal(MustParse("P3M")))
+	g.Expect(Quarter).To(Equal(NewYMD(0, 3, 0)))
+	g.Expect(OneYear).To(Equal(MustParse("P1Y")))
+	g.Expect(OneMonth).To(Equal(MustParse("P1M")))
+	g.Expect(OneWeek).To(Equal(MustParse("P1W")))
+	g.Expect(OneDay).To(Equal(MustParse("P1D")))
+	g.Expect(OneHour).To(Equal(MustParse("PT1H")))
+	g.Expect(OneMinute).To(Equal(MustParse("PT1M")))
+	g.Expect(OneSecond).To(Equal(MustParse("PT1S")))
+}
+
+func TestSinceUntil(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hourAgo := time.Now().Add(-time.Hour)
+	g.Expect(Since(hourAgo).IsPositive()).To(BeTrue())
+	g.Expect(Since(hourAgo).DurationApprox()).To(BeNumerically("~", time.Hour, time.Minute))
+	g.Expect(Until(hourAgo).IsNegative()).To(BeTrue())
+	g.Expect(Until(hourAgo).DurationApprox()).To(BeNumerically("~", -time.Hour, time.Minute))
+
+	inAnHour := time.Now().Add(time.Hour)
+	g.Expect(Until(inAnHour).DurationApprox()).To(BeNumerically("~", time.Hour, time.Minute))
+	g.Expect(Since(inAnHour).DurationApprox()).To(BeNumerically("~", -time.Hour, time.Minute))
+}
+
 //-------------------------------------------------------------------------------------------------
 
 func Test_Period64_Sign_Abs_etc(t *testing.T) {
@@ -556,6 +775,14 @@ func Test_Period64_Sign_Abs_etc(t *testing.T) {
 	g.Expect(z.IsNegative()).To(BeFalse())
 	g.Expect(pos.IsNegative()).To(BeFalse())
 	g.Expect(neg.IsNegative()).To(BeTrue())
+
+	g.Expect(z.IsPos()).To(BeTrue())
+	g.Expect(pos.IsPos()).To(BeTrue())
+	g.Expect(neg.IsPos()).To(BeFalse())
+
+	g.Expect(z.IsNeg()).To(BeFalse())
+	g.Expect(pos.IsNeg()).To(BeFalse())
+	g.Expect(neg.IsNeg()).To(BeTrue())
 }
 
 var (
@@ -629,3 +856,308 @@ func Test_OnlyHMS(t *testing.T) {
 		g.Expect(s).To(Equal(MustParse(c.expect)), info(i, c.expect))
 	}
 }
+
+func Test_Designators(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected []Designator
+	}{
+		{"P0D", nil},
+		{"P1Y2DT3S", []Designator{Year, Day, Second}},
+		{"-P1M1W", []Designator{Month, Week}},
+		{"PT1H-1M", []Designator{Hour, Minute}},
+		{"P1Y2M3W4DT5H6M7S", FieldOrder()},
+	}
+	for i, c := range cases {
+		g.Expect(MustParse(c.value).Designators()).To(Equal(c.expected), info(i, c.value))
+	}
+}
+
+func Test_ReduceToSingleField(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value string
+		field Designator
+		v     decimal.Decimal
+	}{
+		{"P3M", Month, decI(3)},
+		{"-PT5M", Minute, decI(-5)},
+		{"P1.5Y", Year, dec(15, 1)},
+		{"PT0.25S", Second, dec(25, 2)},
+	}
+	for i, c := range cases {
+		d, v, err := MustParse(c.value).ReduceToSingleField()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(d).To(Equal(c.field), info(i, c.value))
+		g.Expect(v).To(Equal(c.v), info(i, c.value))
+	}
+
+	_, _, err := Zero.ReduceToSingleField()
+	g.Expect(err).To(MatchError("P0D has no non-zero fields"))
+
+	_, _, err = MustParse("P1DT-1H").ReduceToSingleField()
+	g.Expect(err).To(MatchError("P1DT-1H has more than one non-zero field: days, hours"))
+}
+
+func Test_MustReduce(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	d, v := MustParse("PT10M").MustReduce()
+	g.Expect(d).To(Equal(Minute))
+	g.Expect(v).To(Equal(decI(10)))
+
+	g.Expect(func() { Zero.MustReduce() }).To(PanicWith(MatchError("P0D has no non-zero fields")))
+	g.Expect(func() { MustParse("PT1H10M").MustReduce() }).To(Panic())
+}
+
+func Test_AllFieldsSameSign(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected bool
+	}{
+		{"P0D", true},
+		{"P1Y2M3W4DT5H6M7S", true},
+		{"-P1Y2M3W4DT5H6M7S", true},
+		{"P1M-1D", false},
+		{"-P1M-1D", false},
+		{"P1YT-1S", false},
+		{"PT1H1M-1S", false},
+		{"P-1Y-1D", true},
+	}
+	for i, c := range cases {
+		p := MustParse(c.value)
+		g.Expect(p.AllFieldsSameSign()).To(Equal(c.expected), info(i, c.value))
+		g.Expect(p.Negate().AllFieldsSameSign()).To(Equal(c.expected), info(i, c.value))
+	}
+}
+
+func Test_Float64_accessors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value                   string
+		days, hours, mins, secs float64
+	}{
+		{"P0D", 0, 0, 0, 0},
+		{"P1W2DT3H4M5S", 9, 3, 4, 5},
+		{"P1W2.5D", 9.5, 0, 0, 0},
+		{"PT1H2M3.25S", 0, 1, 2, 3.25},
+		{"-P1W2DT3H4M5.5S", -9, -3, -4, -5.5},
+		{"P1DT-1.5H", 1, -1.5, 0, 0},
+	}
+	for i, c := range cases {
+		p := MustParse(c.value)
+		g.Expect(p.DaysIncWeeksFloat64()).To(Equal(c.days), info(i, c.value))
+		g.Expect(p.HoursFloat64()).To(Equal(c.hours), info(i, c.value))
+		g.Expect(p.MinutesFloat64()).To(Equal(c.mins), info(i, c.value))
+		g.Expect(p.SecondsFloat64()).To(Equal(c.secs), info(i, c.value))
+	}
+}
+
+func Test_SetNeg(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value      string
+		neg        bool
+		expected   string
+		isNegative bool
+		sign       int
+	}{
+		{"P0D", true, "P0D", false, 0},
+		{"P0D", false, "P0D", false, 0},
+		{"P1Y", true, "-P1Y", true, -1},
+		{"-P1Y", true, "-P1Y", true, -1},
+		{"P1Y", false, "P1Y", false, 1},
+		{"-P1Y", false, "P1Y", false, 1},
+		{"P1M-1D", true, "-P1M-1D", true, -1},
+	}
+	for i, c := range cases {
+		p := MustParse(c.value).SetNeg(c.neg)
+		g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.value))
+		g.Expect(p.IsNegative()).To(Equal(c.isNegative), info(i, c.value))
+		g.Expect(p.Sign()).To(Equal(c.sign), info(i, c.value))
+	}
+}
+
+func Test_IsSubset(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"P0D", "P0D", true},
+		{"P0D", "-P1D", true},
+		{"P1D", "P0D", false},
+		{"P1DT2H", "P3DT2H30M", true},
+		{"P1DT2H", "P3D", false},
+		{"P1DT2H", "-P3DT2H", false},
+		{"-P1DT2H", "-P3DT2H", true},
+		{"P1M-1D", "P1M-2D", true},
+		{"P1M-1D", "P1M2D", false},
+		{"PT90M", "PT2H", false},
+		{"PT1.5S", "PT2S", true},
+		{"PT2.5S", "PT2S", false},
+	}
+	for i, c := range cases {
+		g.Expect(MustParse(c.a).IsSubset(MustParse(c.b))).To(Equal(c.expected), info(i, c.a, c.b))
+	}
+}
+
+func Test_Intersect(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"P1D", "P0D", "P0D"},
+		{"P3DT1H", "P1DT5H", "P1DT1H"},
+		{"P1Y2M", "P2Y1M", "P1Y1M"},
+		{"-P3DT1H", "-P1DT5H", "-P1DT1H"},
+		{"P1M-3D", "P2M-1D", "P1M-1D"},
+		{"PT1.5S", "PT2S", "PT1.5S"},
+	}
+	for i, c := range cases {
+		p, err := MustParse(c.a).Intersect(MustParse(c.b))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.a, c.b))
+	}
+
+	_, err := MustParse("P1M1D").Intersect(MustParse("P1M-1D"))
+	g.Expect(err).To(MatchError("cannot intersect P1M1D with P1M-1D: opposite signs in days"))
+}
+
+func Test_Union(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"P1D", "P0D", "P1D"},
+		{"P3DT1H", "P1DT5H", "P3DT5H"},
+		{"P1Y2M", "P2Y1M", "P2Y2M"},
+		{"-P3DT1H", "-P1DT5H", "-P3DT5H"},
+		{"P1M-3D", "P2M-1D", "P2M-3D"},
+		{"PT1.5S", "PT1M", "PT1M1.5S"},
+	}
+	for i, c := range cases {
+		p, err := MustParse(c.a).Union(MustParse(c.b))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.a, c.b))
+	}
+
+	_, err := MustParse("P1M1D").Union(MustParse("-P1M"))
+	g.Expect(err).To(MatchError("cannot union P1M1D with -P1M: opposite signs in months"))
+
+	_, err = MustParse("P1.5D").Union(MustParse("PT1H"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_IsWholeNumber(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected bool
+	}{
+		{"P0D", true},
+		{"P1Y2M3W4DT5H6M7S", true},
+		{"-P1Y2M3W4DT5H6M7S", true},
+		{"P1.0Y", true},
+		{"P1.5Y", false},
+		{"P1Y0.5M", false},
+		{"PT1H0.001S", false},
+		{"-PT0.5S", false},
+	}
+	for i, c := range cases {
+		g.Expect(MustParse(c.value).IsWholeNumber()).To(Equal(c.expected), info(i, c.value))
+	}
+}
+
+func Test_IsDecimalSafe(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value    string
+		expected bool
+	}{
+		{"P0D", true},
+		{"P1Y2M3W4DT5H6M7S", true},
+		{"-PT0.1S", true},
+		{"PT0.000000001S", true},
+		{"P9007199254740992D", true},
+		{"P12345678901234567D", false},
+		{"PT1.23456789012345678S", false},
+	}
+	for i, c := range cases {
+		g.Expect(MustParse(c.value).IsDecimalSafe()).To(Equal(c.expected), info(i, c.value))
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		one, two string
+		expected string
+	}{
+		{"P0D", "P0D", "P0D"},
+		{"P1Y2M3D", "PT4H5M6S", "P1Y2M3DT4H5M6S"},
+		{"PT4H5M6S", "P1Y2M3D", "P1Y2M3DT4H5M6S"},
+		{"P1Y", "P0D", "P1Y"},
+		{"-P1Y", "PT1H", "-P1YT-1H"},
+		{"-P1Y", "-PT1H", "-P1YT1H"},
+		{"P1W", "P2D", "P1W2D"},
+		{"P1Y", "PT0.5S", "P1YT0.5S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.one, c.two), func(t *testing.T) {
+			p, err := MustParse(c.one).Merge(MustParse(c.two))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.expected))
+		})
+	}
+
+	_, err := MustParse("P1Y2DT3H").Merge(MustParse("P2DT3H4M"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("cannot merge P1Y2DT3H with P2DT3H4M: both have non-zero days, hours"))
+
+	_, err = MustParse("P1.5Y").Merge(MustParse("P2D"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRepeat(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := MustParse("P1M")
+	g.Expect(p.Repeat(0)).To(BeEmpty())
+	g.Expect(p.Repeat(-1)).To(BeEmpty())
+	g.Expect(p.Repeat(3)).To(Equal([]Period{p, p, p}))
+
+	list := p.RepeatWith(3, func(i int, p Period) Period {
+		return p.SetInt(i+1, Month)
+	})
+	g.Expect(list).To(Equal([]Period{MustParse("P1M"), MustParse("P2M"), MustParse("P3M")}))
+}
+
+func TestApply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := MustParse("PT90M")
+	g.Expect(p.Apply()).To(Equal(p))
+
+	normalise := func(p Period) Period { return p.Normalise(true) }
+	g.Expect(p.Apply(normalise)).To(Equal(MustParse("PT1H30M")))
+	g.Expect(p.Apply(normalise, Period.Negate)).To(Equal(MustParse("-PT1H30M")))
+	g.Expect(p.Apply(Period.Negate, Period.Abs, normalise)).To(Equal(MustParse("PT1H30M")))
+}
@@ -0,0 +1,144 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+
+	"github.com/govalues/decimal"
+)
+
+// TruncateTo keeps only the fields from unit upward within the same part of the period, i.e.
+// either the date part (years, months, weeks, days) or the clock part (hours, minutes, seconds).
+// All other fields become zero and any fraction in the unit field is discarded. Fractions in the
+// more significant fields are kept, so for example "P0.5Y0.5M1.5D" (the result of Mul) gives
+// "P0.5Y0.5M1D" for TruncateTo(Day).
+//
+// For example, TruncateTo(Hour) on "P1Y2M3DT4H5M6S" gives "PT4H", TruncateTo(Minute) gives
+// "PT4H5M" and TruncateTo(Month) gives "P1Y2M".
+//
+// A panic arises if the unit is unknown.
+func (period Period) TruncateTo(unit Designator) Period {
+	if unit < Second || unit > Year {
+		panic(unit)
+	}
+
+	fields := [7]decimal.Decimal{period.years, period.months, period.weeks, period.days, period.hours, period.minutes, period.seconds}
+	for i, d := range FieldOrder() {
+		switch {
+		case isClock(d) != isClock(unit), d < unit:
+			fields[i] = decimal.Zero
+		case d == unit:
+			fields[i] = fields[i].Trunc(0)
+		}
+	}
+
+	return Period{
+		years: fields[0], months: fields[1], weeks: fields[2], days: fields[3],
+		hours: fields[4], minutes: fields[5], seconds: fields[6],
+		neg: period.neg,
+	}.NormaliseSign()
+}
+
+// splitAt separates the period into the whole fields from unit upward, in FieldOrder, and the
+// remainder, which consists of the fraction of the unit field and all less significant fields.
+// The field values carry the sign of the period.
+func (period Period) splitAt(unit Designator) (fields [7]decimal.Decimal, remainder Period) {
+	if unit < Second || unit > Year {
+		panic(unit)
+	}
+
+	var rest [7]decimal.Decimal
+	for i, d := range FieldOrder() {
+		v := period.GetField(d)
+		switch {
+		case d > unit:
+			fields[i] = v
+		case d == unit:
+			fields[i] = v.Trunc(0)
+			rest[i], _ = v.Sub(fields[i])
+		default:
+			rest[i] = v
+		}
+	}
+
+	remainder, _ = NewDecimal(rest[0], rest[1], rest[2], rest[3], rest[4], rest[5], rest[6])
+	return fields, remainder
+}
+
+func isClock(d Designator) bool {
+	return d <= Hour
+}
+
+// Ceil rounds the period up to a whole number of units, analogous to math.Ceil. Any fraction in
+// the unit field and all less significant fields are carried into the unit field, which is then
+// rounded up; the less significant fields become zero. Negative periods are flipped to positive
+// before rounding and flipped back afterwards, so their magnitude is rounded up too.
+//
+// For example, Ceil(Minute) on "PT1M30S" gives "PT2M" and on "-PT1M30S" it gives "-PT2M".
+// Days are treated as distinct from hours, so Ceil(Day) on "P1DT2H" gives "P2D".
+//
+// If the calculations would lead to arithmetic errors, the current values are kept unaltered.
+// A panic arises if the unit is unknown.
+func (period Period) Ceil(unit Designator) Period {
+	if period.neg {
+		return period.Abs().roundTowards(unit, 1).Negate()
+	}
+	return period.roundTowards(unit, 1)
+}
+
+func (period Period) roundTowards(unit Designator, direction int) Period {
+	fields, remainder := period.splitAt(unit)
+
+	if remainder.Sign() == direction {
+		i := int(Year - unit)
+		v, err := fields[i].Add(decimal.MustNew(int64(direction), 0))
+		if err != nil {
+			return period
+		}
+		fields[i] = v
+	}
+
+	p, err := NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+	if err != nil {
+		return period
+	}
+	return p
+}
+
+// Floor rounds the period down to a whole number of units, analogous to math.Floor. The unit
+// field is rounded down and the less significant fields become zero; unlike Ceil, nothing is
+// carried. Negative periods are flipped to positive before rounding and flipped back afterwards,
+// so their magnitude is rounded down too.
+//
+// For example, Floor(Minute) on "PT1M30S" gives "PT1M" and on "-PT1M30S" it gives "-PT1M".
+//
+// If the calculations would lead to arithmetic errors, the current values are kept unaltered.
+// A panic arises if the unit is unknown.
+func (period Period) Floor(unit Designator) Period {
+	if period.neg {
+		return period.Abs().roundTowards(unit, -1).Negate()
+	}
+	return period.roundTowards(unit, -1)
+}
+
+// WithPrecision rounds every field to at most maxScale decimal places, using rounding half to
+// even (banker's rounding). This is useful for storage systems that have limited decimal
+// precision. For example, WithPrecision(1) on "PT1.25S" gives "PT1.2S".
+//
+// An error arises if maxScale is negative or if the result would have multiple fields with
+// fractions (see NewDecimal).
+func (period Period) WithPrecision(maxScale int) (Period, error) {
+	if maxScale < 0 {
+		return period, fmt.Errorf("cannot round %s to a negative precision %d", period, maxScale)
+	}
+
+	var fields [7]decimal.Decimal
+	for i, d := range FieldOrder() {
+		fields[i] = period.GetField(d).Round(maxScale)
+	}
+
+	return NewDecimal(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+}
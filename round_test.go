@@ -0,0 +1,164 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTruncateTo(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		unit     Designator
+		expected string
+	}{
+		{"P0D", Hour, "P0D"},
+		{"P1Y2M3DT4H5M6S", Second, "PT4H5M6S"},
+		{"P1Y2M3DT4H5M6S", Minute, "PT4H5M"},
+		{"P1Y2M3DT4H5M6S", Hour, "PT4H"},
+		{"P1Y2M3DT4H5M6S", Day, "P1Y2M3D"},
+		{"P1Y2M3DT4H5M6S", Month, "P1Y2M"},
+		{"P1Y2M3DT4H5M6S", Year, "P1Y"},
+		{"P1Y2M3W", Week, "P1Y2M3W"},
+		{"P1Y2M3W", Day, "P1Y2M3W"},
+		{"PT4H5M6S", Day, "P0D"},
+		{"P3D", Hour, "P0D"},
+		{"PT1H2.5M", Minute, "PT1H2M"},
+		{"PT2.5H", Second, "PT2.5H"},
+		{"-P1Y2M3DT4H5M6S", Minute, "-PT4H5M"},
+		{"-PT1H2.5M", Minute, "-PT1H2M"},
+		{"P1Y-2M", Month, "P1Y-2M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.period, c.unit), func(t *testing.T) {
+			p := MustParse(c.period).TruncateTo(c.unit)
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.period, c.unit))
+		})
+	}
+
+	// more than one field may hold a fraction after Mul; this must not panic
+	half, err := MustParse("P1Y1M3D").Mul(dec(5, 1))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(half.String()).To(Equal("P0.5Y0.5M1.5D"))
+	g.Expect(half.TruncateTo(Day).String()).To(Equal("P0.5Y0.5M1D"))
+	g.Expect(half.TruncateTo(Month).String()).To(Equal("P0.5Y"))
+}
+
+func TestCeil(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		unit     Designator
+		expected string
+	}{
+		{"P0D", Hour, "P0D"},
+		{"PT1M", Minute, "PT1M"},
+		{"PT1M30S", Minute, "PT2M"},
+		{"PT1M0.001S", Minute, "PT2M"},
+		{"PT1.5M", Minute, "PT2M"},
+		{"PT30S", Minute, "PT1M"},
+		{"PT59M30S", Minute, "PT60M"},
+		{"PT1H1S", Hour, "PT2H"},
+		{"P1DT2H", Day, "P2D"},
+		{"P1Y2M3DT4H5M6S", Month, "P1Y3M"},
+		{"P1Y2M3DT4H5M6S", Second, "P1Y2M3DT4H5M6S"},
+		{"P1M-1D", Month, "P1M"},
+		{"P1M1W", Week, "P1M1W"},
+		{"P1M1W1D", Week, "P1M2W"},
+		{"-PT1M30S", Minute, "-PT2M"},
+		{"-PT30S", Minute, "-PT1M"},
+		{"-PT1.5M", Minute, "-PT2M"},
+		{"-PT1M", Minute, "-PT1M"},
+		{"-P1Y2M3D", Year, "-P2Y"},
+		{"-P1M-1D", Month, "-P1M"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.period, c.unit), func(t *testing.T) {
+			p := MustParse(c.period).Ceil(c.unit)
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.period, c.unit))
+			g.Expect(p.Negate()).To(Equal(MustParse(c.period).Negate().Ceil(c.unit)), info(i, c.period, c.unit))
+		})
+	}
+}
+
+func TestFloor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		unit     Designator
+		expected string
+	}{
+		{"P0D", Hour, "P0D"},
+		{"PT1M", Minute, "PT1M"},
+		{"PT1M30S", Minute, "PT1M"},
+		{"PT1.5M", Minute, "PT1M"},
+		{"PT30S", Minute, "P0D"},
+		{"P1DT2H", Day, "P1D"},
+		{"P1Y2M3DT4H5M6S", Year, "P1Y"},
+		{"P1Y2M3DT4H5M6S", Second, "P1Y2M3DT4H5M6S"},
+		{"P1M-1D", Month, "P0D"},
+		{"P1Y1M-1D", Month, "P1Y"},
+		{"-PT1M30S", Minute, "-PT1M"},
+		{"-PT30S", Minute, "P0D"},
+		{"-PT1.5M", Minute, "-PT1M"},
+		{"-PT1M", Minute, "-PT1M"},
+		{"-P1Y2M3D", Year, "-P1Y"},
+		{"-P1Y1M-1D", Month, "-P1Y"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %s", i, c.period, c.unit), func(t *testing.T) {
+			p := MustParse(c.period).Floor(c.unit)
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.period, c.unit))
+			g.Expect(p.Negate()).To(Equal(MustParse(c.period).Negate().Floor(c.unit)), info(i, c.period, c.unit))
+		})
+	}
+}
+
+func TestWithPrecision(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period   string
+		scale    int
+		expected string
+	}{
+		{"P0D", 3, "P0D"},
+		{"P1Y2M3DT4H5M6S", 0, "P1Y2M3DT4H5M6S"},
+		{"PT1.25S", 1, "PT1.2S"},
+		{"PT1.35S", 1, "PT1.4S"},
+		{"PT1.123456S", 3, "PT1.123S"},
+		{"PT1.5S", 0, "PT2S"},
+		{"PT1.5S", 3, "PT1.5S"},
+		{"P1Y2.5M", 0, "P1Y2M"},
+		{"PT0.0001S", 3, "P0D"},
+		{"-PT1.0006S", 3, "-PT1.001S"},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d %s %d", i, c.period, c.scale), func(t *testing.T) {
+			p, err := MustParse(c.period).WithPrecision(c.scale)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(p).To(Equal(MustParse(c.expected)), info(i, c.period, c.scale))
+		})
+	}
+
+	_, err := MustParse("PT1S").WithPrecision(-1)
+	g.Expect(err).To(MatchError("cannot round PT1S to a negative precision -1"))
+
+	// a non-standard period with two fractions
+	p, _ := NewDecimal(decS("1.5"), decS("2.5"), decI(0), decI(0), decI(0), decI(0), decI(0))
+	_, err = p.WithPrecision(1)
+	g.Expect(err).To(HaveOccurred())
+
+	p, err = p.WithPrecision(0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(Equal(MustParse("P2Y2M")))
+}
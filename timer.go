@@ -0,0 +1,34 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"context"
+	"time"
+)
+
+// ForContext converts the period to a duration using DurationApprox, limited to the time
+// remaining before the context's deadline, if it has one. This prevents a timeout longer than
+// the context deadline, which is a common bug. The result is zero if the deadline has passed.
+func (period Period) ForContext(ctx context.Context) time.Duration {
+	d := period.DurationApprox()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := max(time.Until(deadline), 0)
+		d = min(d, remaining)
+	}
+
+	return d
+}
+
+// Timer creates a timer that fires when the period, starting at start, has elapsed. The period is
+// converted using DurationApprox. The result is nil if the end of the period has already passed.
+func (period Period) Timer(start time.Time) *time.Timer {
+	d := time.Until(start.Add(period.DurationApprox()))
+	if d <= 0 {
+		return nil
+	}
+	return time.NewTimer(d)
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package period
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestForContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := MustParse("PT1H")
+	g.Expect(p.ForContext(context.Background())).To(Equal(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	g.Expect(p.ForContext(ctx)).To(Equal(time.Hour))
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	g.Expect(p.ForContext(ctx)).To(BeNumerically("~", time.Minute, time.Second))
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Minute))
+	defer cancel()
+	g.Expect(p.ForContext(ctx)).To(BeZero())
+}
+
+func TestTimer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(MustParse("PT1S").Timer(time.Now().Add(-time.Minute))).To(BeNil())
+
+	timer := MustParse("PT0.01S").Timer(time.Now())
+	g.Expect(timer).NotTo(BeNil())
+	g.Eventually(timer.C).Should(Receive())
+
+	timer = MustParse("PT1H").Timer(time.Now())
+	g.Expect(timer).NotTo(BeNil())
+	g.Expect(timer.Stop()).To(BeTrue())
+}
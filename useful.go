@@ -12,13 +12,11 @@ type usefulWriter interface {
 	io.StringWriter
 }
 
-func adapt(w io.Writer) usefulWriter {
-	if uw, is := w.(usefulWriter); is {
-		return uw
-	}
+func adapt(w io.Writer) *uw {
 	return &uw{w: w}
 }
 
+// uw counts the bytes written and retains the first error, after which it writes nothing more.
 type uw struct {
 	w   io.Writer
 	sum int
@@ -36,17 +34,44 @@ func (u *uw) Write(p []byte) (n int, err error) {
 }
 
 func (u *uw) WriteString(s string) (n int, err error) {
+	if u.err != nil {
+		return 0, u.err
+	}
+	if sw, ok := u.w.(io.StringWriter); ok {
+		n, err = sw.WriteString(s)
+		u.sum += n
+		u.err = err
+		return n, err
+	}
 	return u.Write([]byte(s))
 }
 
 func (u *uw) WriteByte(b byte) error {
+	if u.err != nil {
+		return u.err
+	}
+	if bw, ok := u.w.(io.ByteWriter); ok {
+		u.err = bw.WriteByte(b)
+		if u.err == nil {
+			u.sum++
+		}
+		return u.err
+	}
 	_, err := u.Write([]byte{b})
 	return err
 }
 
-func uwSum(u usefulWriter) (int64, error) {
-	if buf, ok := u.(*uw); ok {
-		return int64(buf.sum), buf.err
-	}
-	return 0, nil
+func uwSum(u *uw) (int64, error) {
+	return int64(u.sum), u.err
+}
+
+// byteReader adapts an io.Reader to io.ByteReader without reading ahead.
+type byteReader struct {
+	r io.Reader
+	b [1]byte
+}
+
+func (br *byteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(br.r, br.b[:])
+	return br.b[0], err
 }